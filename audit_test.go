@@ -0,0 +1,111 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestLogAuditRequiredFields(t *testing.T) {
+	valid := AuditEntry{MethodName: "SetIamPolicy", ResourceName: "projects/p", PrincipalEmail: "a@example.com"}
+	for _, tt := range []struct {
+		name    string
+		edit    func(*AuditEntry)
+		wantErr string
+	}{
+		{"valid", func(*AuditEntry) {}, ""},
+		{"missing method", func(e *AuditEntry) { e.MethodName = "" }, "methodName"},
+		{"missing resource", func(e *AuditEntry) { e.ResourceName = "" }, "resourceName"},
+		{"missing principal", func(e *AuditEntry) { e.PrincipalEmail = "" }, "principalEmail"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			e := valid
+			tt.edit(&e)
+			var out bytes.Buffer
+			l := newTestLogger(io.Discard, WithLogfmtSink(&out))
+			err := l.LogAudit(context.Background(), e)
+			if tt.wantErr == "" {
+				if err != nil || out.Len() == 0 {
+					t.Fatalf("LogAudit = %v, output %q, want an entry", err, out.String())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LogAudit error = %v, want one naming %s", err, tt.wantErr)
+			}
+			if out.Len() != 0 {
+				t.Errorf("LogAudit logged %q for an invalid entry", out.String())
+			}
+		})
+	}
+}
+
+func TestLogAuditPayload(t *testing.T) {
+	var out bytes.Buffer
+	l := newTestLogger(io.Discard, WithSchemaVersion("3"), WithKeyCase(Snake), WithKeyPrefix("app."))
+	l.stdout = &jsonSink{w: &out}
+	err := l.LogAudit(context.Background(), AuditEntry{
+		Severity:       logging.Info,
+		ServiceName:    "iam.googleapis.com",
+		MethodName:     "SetIamPolicy",
+		ResourceName:   "projects/p",
+		PrincipalEmail: "a@example.com",
+		CallerIP:       "10.0.0.1",
+		StatusCode:     7,
+		StatusMessage:  "denied",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+		t.Fatalf("%v: %s", err, out.String())
+	}
+	want := map[string]interface{}{
+		"@type":              auditLogType,
+		"serviceName":        "iam.googleapis.com",
+		"methodName":         "SetIamPolicy",
+		"resourceName":       "projects/p",
+		"authenticationInfo": map[string]interface{}{"principalEmail": "a@example.com"},
+		"requestMetadata":    map[string]interface{}{"callerIp": "10.0.0.1"},
+		"status":             map[string]interface{}{"code": 7.0, "message": "denied"},
+		"schema_version":     "3",
+		"severity":           "NOTICE",
+	}
+	for k, v := range want {
+		if !reflect.DeepEqual(line[k], v) {
+			t.Errorf("%s = %#v, want %#v", k, line[k], v)
+		}
+	}
+}
+
+func TestLogAuditSeverityUsesComparator(t *testing.T) {
+	// A comparator ranking Debug above everything else.
+	debugFirst := func(a, b logging.Severity) int {
+		rank := func(s logging.Severity) int {
+			if s == logging.Debug {
+				return 1000
+			}
+			return int(s)
+		}
+		return rank(a) - rank(b)
+	}
+	var out bytes.Buffer
+	l := newTestLogger(io.Discard, WithLogfmtSink(&out), WithSeverityComparator(debugFirst))
+	l.LogAudit(context.Background(), AuditEntry{
+		Severity:       logging.Debug,
+		MethodName:     "SetIamPolicy",
+		ResourceName:   "projects/p",
+		PrincipalEmail: "a@example.com",
+	})
+	if !strings.Contains(out.String(), "severity=DEBUG") {
+		t.Errorf("line = %q, want Debug kept as it ranks above Notice", out.String())
+	}
+}
@@ -0,0 +1,79 @@
+package cloudlogging
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// background tracks the goroutines started by options so that Close can stop
+// them and wait for them in one place.
+type background struct {
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	stop    chan struct{}
+	stopped bool
+}
+
+// goBackground runs fn in a tracked goroutine. stop is closed when the logger
+// is closed; fn must return soon after. Nothing is started once the logger is
+// closed.
+func (l *Logger) goBackground(fn func(stop <-chan struct{})) {
+	b := &l.bg
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopped {
+		return
+	}
+	if b.stop == nil {
+		b.stop = make(chan struct{})
+	}
+	b.wg.Add(1)
+	go func(stop <-chan struct{}) {
+		defer b.wg.Done()
+		fn(stop)
+	}(b.stop)
+}
+
+// stopBackground signals every background goroutine to stop and waits for
+// them until ctx is done.
+func (l *Logger) stopBackground(ctx context.Context) error {
+	b := &l.bg
+	b.mu.Lock()
+	if !b.stopped {
+		b.stopped = true
+		if b.stop != nil {
+			close(b.stop)
+		}
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// every runs fn in a background goroutine every interval until the logger is
+// closed.
+func (l *Logger) every(interval time.Duration, fn func()) {
+	l.goBackground(func(stop <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fn()
+			}
+		}
+	})
+}
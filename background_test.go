@@ -0,0 +1,46 @@
+package cloudlogging
+
+import (
+	"context"
+	"io"
+	"log"
+	"runtime"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestCloseStopsBackgroundGoroutines(t *testing.T) {
+	srv := &fakeServer{}
+	serve := srv.serve(t)
+	before := runtime.NumGoroutine()
+
+	l, err := New(context.Background(), "test-project", "test", log.New(io.Discard, "", 0),
+		serve,
+		WithHeartbeat(time.Millisecond),
+		WithRuntimeStats(time.Millisecond),
+		WithDeliveryWatchdog(time.Millisecond),
+		WithFlushOnSeverity(logging.Error),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		l.Error("flush me")
+		time.Sleep(time.Millisecond)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The client's connection goroutines exit asynchronously once closed.
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		buf := make([]byte, 1<<16)
+		t.Errorf("%d goroutines after close, %d before:\n%s", after, before, buf[:runtime.Stack(buf, true)])
+	}
+}
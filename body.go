@@ -0,0 +1,58 @@
+package cloudlogging
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"unicode/utf8"
+
+	"cloud.google.com/go/logging"
+)
+
+// LogBodySample logs msg with at most maxBytes of body under the "body" key,
+// for debugging API calls. A truncated body is marked with
+// "body_truncated" and its full size in "body_size". Bodies that are not
+// valid UTF-8 are base64 encoded and marked with "body_encoding".
+func (l *Logger) LogBodySample(ctx context.Context, severity logging.Severity, msg string, body []byte, maxBytes int) {
+	text := utf8.Valid(body)
+	sample := body
+	if maxBytes >= 0 && len(sample) > maxBytes {
+		sample = sample[:maxBytes]
+		if text {
+			for len(sample) > 0 && !utf8.Valid(sample) {
+				sample = sample[:len(sample)-1]
+			}
+		}
+	}
+
+	data := payload(msg, "body_size", strconv.Itoa(len(body)))
+	if len(sample) < len(body) {
+		data["body_truncated"] = "true"
+	}
+	if text {
+		data["body"] = string(sample)
+	} else {
+		data["body"] = base64.StdEncoding.EncodeToString(sample)
+		data["body_encoding"] = "base64"
+	}
+	l.logContext(ctx, severity, data)
+}
+
+// LogWithAttachment logs msg with at most maxBytes of data base64 encoded under
+// "attachment.<name>", and the original length under "attachment.<name>.size",
+// to keep small binary diagnostics inline. A truncated attachment is marked
+// with "attachment.<name>.truncated".
+func (l *Logger) LogWithAttachment(ctx context.Context, severity logging.Severity, msg string, name string, data []byte, maxBytes int) {
+	key := "attachment." + name
+	sample := data
+	if maxBytes >= 0 && len(sample) > maxBytes {
+		sample = sample[:maxBytes]
+	}
+
+	fields := payload(msg, key+".size", strconv.Itoa(len(data)))
+	fields[key] = base64.StdEncoding.EncodeToString(sample)
+	if len(sample) < len(data) {
+		fields[key+".truncated"] = "true"
+	}
+	l.logContext(ctx, severity, fields)
+}
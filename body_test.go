@@ -0,0 +1,58 @@
+package cloudlogging
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestLogBodySample(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		body     []byte
+		maxBytes int
+		want     map[string]string
+	}{
+		{"short", []byte(`{"ok":true}`), 64, map[string]string{"body": `{"ok":true}`, "body_size": "11"}},
+		{"truncated", []byte(`{"items":[1,2,3]}`), 9, map[string]string{"body": `{"items":`, "body_size": "17", "body_truncated": "true"}},
+		{"rune boundary", []byte("héllo"), 2, map[string]string{"body": "h", "body_size": "6", "body_truncated": "true"}},
+		{"binary", []byte{0xff, 0xfe, 0x00, 0x01}, 64, map[string]string{"body": "//4AAQ==", "body_size": "4", "body_encoding": "base64"}},
+		{"binary truncated", []byte{0xff, 0xfe, 0x00, 0x01}, 2, map[string]string{"body": "//4=", "body_size": "4", "body_encoding": "base64", "body_truncated": "true"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLogger(io.Discard)
+			entries := l.Capture(func() {
+				l.LogBodySample(context.Background(), logging.Debug, "response", tt.body, tt.maxBytes)
+			})
+			if got := entries[0].Details; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("details = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogWithAttachment(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		data     []byte
+		maxBytes int
+		want     map[string]string
+	}{
+		{"whole", []byte{0x0a, 0x03, 'f', 'o', 'o'}, 16, map[string]string{"attachment.req": "CgNmb28=", "attachment.req.size": "5"}},
+		{"truncated", []byte{0x0a, 0x03, 'f', 'o', 'o'}, 3, map[string]string{"attachment.req": "CgNm", "attachment.req.size": "5", "attachment.req.truncated": "true"}},
+		{"empty", nil, 16, map[string]string{"attachment.req": "", "attachment.req.size": "0"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLogger(io.Discard)
+			entries := l.Capture(func() {
+				l.LogWithAttachment(context.Background(), logging.Warning, "bad request", "req", tt.data, tt.maxBytes)
+			})
+			if got := entries[0].Details; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("details = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package cloudlogging
+
+import (
+	"strings"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// EntryBuilder builds an entry field by field. It is created by Logger.Entry
+// and sent with Send, which goes through the same filtering and enrichment as
+// the other logging methods.
+type EntryBuilder struct {
+	logger   *Logger
+	severity logging.Severity
+	msg      string
+	details  []string
+	labels   map[string]string
+	at       time.Time
+	trace    string
+	logName  string
+}
+
+func (l *Logger) Entry() *EntryBuilder {
+	return &EntryBuilder{logger: l}
+}
+
+func (b *EntryBuilder) Severity(severity logging.Severity) *EntryBuilder {
+	b.severity = severity
+	return b
+}
+
+func (b *EntryBuilder) Message(msg string) *EntryBuilder {
+	b.msg = msg
+	return b
+}
+
+func (b *EntryBuilder) Detail(key, value string) *EntryBuilder {
+	b.details = append(b.details, key, value)
+	return b
+}
+
+func (b *EntryBuilder) Label(key, value string) *EntryBuilder {
+	if b.labels == nil {
+		b.labels = make(map[string]string)
+	}
+	b.labels[key] = value
+	return b
+}
+
+// At sets the entry timestamp. By default the client uses the time of Send.
+func (b *EntryBuilder) At(t time.Time) *EntryBuilder {
+	b.at = t
+	return b
+}
+
+// Trace sets the trace, either as a bare trace ID or as a full
+// "projects/<id>/traces/<trace>" resource name.
+func (b *EntryBuilder) Trace(trace string) *EntryBuilder {
+	b.trace = trace
+	return b
+}
+
+func (b *EntryBuilder) Send() {
+	l := b.logger
+	data := payload(b.msg, b.details...)
+	l.enrich(b.severity, data)
+	entry := logging.Entry{
+		Payload:   data,
+		Severity:  b.severity,
+		Labels:    b.labels,
+		Timestamp: b.at,
+		LogName:   b.logName,
+	}
+	if b.trace != "" {
+		entry.Trace = b.trace
+		if !strings.HasPrefix(b.trace, "projects/") {
+			entry.Trace = l.traceName(b.trace)
+		}
+	}
+	l.write(entry, data)
+}
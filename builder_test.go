@@ -0,0 +1,56 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestEntryBuilder(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLogger(io.Discard, WithMinSeverity(logging.Info))
+
+	entries := l.Capture(func() {
+		l.Entry().Severity(logging.Warning).Message("disk almost full").Send()
+		l.Entry().Severity(logging.Error).Message("payment failed").
+			Detail("user", "alice").Detail("amount", "12").
+			Label("region", "eu").At(at).Send()
+		l.Entry().Severity(logging.Debug).Message("filtered").Send()
+	})
+
+	want := []Entry{
+		{Severity: logging.Warning, Message: "disk almost full", Details: map[string]string{}, Labels: map[string]string{}},
+		{Severity: logging.Error, Message: "payment failed", Details: map[string]string{"user": "alice", "amount": "12"}, Labels: map[string]string{"region": "eu"}, Timestamp: at},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v\nwant %+v", entries, want)
+	}
+}
+
+func TestEntryBuilderTrace(t *testing.T) {
+	for _, tt := range []struct {
+		trace, want string
+	}{
+		{"4bf92f3577b34da6a3ce929d0e0e4736", "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"projects/other/traces/4bf92f3577b34da6a3ce929d0e0e4736", "projects/other/traces/4bf92f3577b34da6a3ce929d0e0e4736"},
+	} {
+		var out bytes.Buffer
+		l := newTestLogger(io.Discard, WithStdoutJSON())
+		l.stdout.w = &out
+		l.projectID = "my-project"
+		l.Entry().Severity(logging.Info).Message("traced").Trace(tt.trace).Send()
+
+		var line map[string]interface{}
+		if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+			t.Fatalf("%v: %s", err, out.String())
+		}
+		if got := line[jsonTraceKey]; got != tt.want {
+			t.Errorf("Trace(%q): trace = %v, want %q", tt.trace, got, tt.want)
+		}
+	}
+}
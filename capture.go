@@ -0,0 +1,69 @@
+package cloudlogging
+
+import (
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// Entry is a logged entry as returned by Capture.
+type Entry struct {
+	Severity  logging.Severity
+	Message   string
+	Details   map[string]string
+	Labels    map[string]string
+	Timestamp time.Time
+}
+
+// Capture runs fn and returns the entries logged through l meanwhile, instead
+// of delivering them. Entries from other goroutines logging through l during
+// fn are captured too. Nested captures compose: an entry is returned by every
+// capture active when it was logged.
+func (l *Logger) Capture(fn func()) []Entry {
+	captured := new([]Entry)
+	l.capMu.Lock()
+	l.captures = append(l.captures, captured)
+	l.capMu.Unlock()
+
+	defer func() {
+		l.capMu.Lock()
+		for i, c := range l.captures {
+			if c == captured {
+				l.captures = append(l.captures[:i], l.captures[i+1:]...)
+				break
+			}
+		}
+		l.capMu.Unlock()
+	}()
+
+	fn()
+
+	l.capMu.Lock()
+	defer l.capMu.Unlock()
+	return *captured
+}
+
+// capture records entry in the active captures, reporting whether there were
+// any.
+func (l *Logger) capture(entry logging.Entry, backupData interface{}) bool {
+	l.capMu.Lock()
+	defer l.capMu.Unlock()
+	if len(l.captures) == 0 {
+		return false
+	}
+
+	e := Entry{
+		Severity:  entry.Severity,
+		Labels:    copyMap(entry.Labels),
+		Timestamp: entry.Timestamp,
+	}
+	if data, ok := backupData.(map[string]string); ok {
+		e.Details = copyMap(data)
+		e.Message = e.Details["msg"]
+		delete(e.Details, "msg")
+	}
+	for _, c := range l.captures {
+		*c = append(*c, e)
+	}
+	return true
+}
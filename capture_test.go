@@ -0,0 +1,44 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestCaptureNested(t *testing.T) {
+	var out bytes.Buffer
+	l := newTestLogger(&out)
+
+	var inner []Entry
+	outer := l.Capture(func() {
+		l.Info("outer before")
+		inner = l.Capture(func() {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				l.Info("inner goroutine")
+			}()
+			<-done
+		})
+		l.Info("outer after")
+	})
+	l.Info("not captured")
+
+	messages := func(entries []Entry) []string {
+		var m []string
+		for _, e := range entries {
+			m = append(m, e.Message)
+		}
+		return m
+	}
+	if got, want := messages(outer), []string{"outer before", "inner goroutine", "outer after"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("outer capture = %q, want %q", got, want)
+	}
+	if got, want := messages(inner), []string{"inner goroutine"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("inner capture = %q, want %q", got, want)
+	}
+	if got, want := out.String(), "Info      : map[msg:not captured]\n"; got != want {
+		t.Errorf("delivered %q, want only the entry logged outside captures, %q", got, want)
+	}
+}
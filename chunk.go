@@ -0,0 +1,96 @@
+package cloudlogging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"strconv"
+
+	"cloud.google.com/go/logging"
+	logpb "google.golang.org/genproto/googleapis/logging/v2"
+)
+
+// WithChunking splits an entry with more than maxFields details into several
+// entries of at most maxFields details each, instead of risking an entry too
+// large for Cloud Logging. The parts carry the message, the fields added by
+// options, a "part" field such as "2/3" and the same operation ID, so they
+// can be reassembled with a query on operation.id.
+func WithChunking(maxFields int) Option {
+	return func(l *Logger) {
+		l.chunkFields = maxFields
+	}
+}
+
+// deliverChunked delivers entry in parts when it has too many details for
+// WithChunking, or as is otherwise. Only the caller's details are split; the
+// fields added by options are repeated in every part.
+func (l *Logger) deliverChunked(entry logging.Entry, backupData interface{}) {
+	data, ok := backupData.(map[string]string)
+	if l.chunkFields <= 0 || !ok {
+		l.deliver(entry, backupData)
+		return
+	}
+
+	var keys []string
+	shared := map[string]string{"msg": data["msg"]}
+	for k, v := range data {
+		if k == "msg" {
+			continue
+		}
+		if l.addedByOption(k) {
+			shared[k] = v
+		} else {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) <= l.chunkFields {
+		l.deliver(entry, backupData)
+		return
+	}
+	sort.Strings(keys)
+
+	id := operationID()
+	parts := (len(keys) + l.chunkFields - 1) / l.chunkFields
+	for part := 0; part < parts; part++ {
+		end := (part + 1) * l.chunkFields
+		if end > len(keys) {
+			end = len(keys)
+		}
+		chunk := copyMap(shared)
+		for _, k := range keys[part*l.chunkFields : end] {
+			chunk[k] = data[k]
+		}
+		chunk["part"] = strconv.Itoa(part+1) + "/" + strconv.Itoa(parts)
+
+		e := entry
+		e.Payload = chunk
+		e.Operation = &logpb.LogEntryOperation{
+			Id:       id,
+			Producer: l.name,
+			First:    part == 0,
+			Last:     part == parts-1,
+		}
+		l.deliver(e, chunk)
+	}
+}
+
+// addedByOption reports whether key is one of the fields options add to
+// every entry, such as those of WithSchemaFields and WithSchemaVersion.
+func (l *Logger) addedByOption(key string) bool {
+	if _, ok := l.fields[key]; ok {
+		return true
+	}
+	for _, field := range l.schemaFields {
+		if key == field {
+			return true
+		}
+	}
+	return key == "schema_version" && l.schemaVersion != "" ||
+		key == "goid" && l.goroutineID
+}
+
+func operationID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
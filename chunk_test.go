@@ -0,0 +1,51 @@
+package cloudlogging
+
+import (
+	"io"
+	"strconv"
+	"testing"
+)
+
+func TestChunkingRepeatsOptionFields(t *testing.T) {
+	l := newTestLogger(io.Discard,
+		WithChunking(2),
+		WithSchemaVersion("v3"),
+		WithSchemaFields("tenant"),
+	)
+
+	var details []string
+	for i := 0; i < 5; i++ {
+		details = append(details, "k"+strconv.Itoa(i), strconv.Itoa(i))
+	}
+	entries := l.Capture(func() { l.Info("big", details...) })
+
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	seen := make(map[string]bool)
+	for i, e := range entries {
+		if e.Message != "big" {
+			t.Errorf("part %d: message = %q", i, e.Message)
+		}
+		if e.Details["schema_version"] != "v3" {
+			t.Errorf("part %d: schema_version = %q, want v3", i, e.Details["schema_version"])
+		}
+		if _, ok := e.Details["tenant"]; !ok {
+			t.Errorf("part %d: missing schema field tenant", i)
+		}
+		if want := strconv.Itoa(i+1) + "/3"; e.Details["part"] != want {
+			t.Errorf("part %d: part = %q, want %q", i, e.Details["part"], want)
+		}
+		for k := range e.Details {
+			if len(k) == 2 && k[0] == 'k' {
+				if seen[k] {
+					t.Errorf("detail %s in more than one part", k)
+				}
+				seen[k] = true
+			}
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("got %d caller details across parts, want 5", len(seen))
+	}
+}
@@ -0,0 +1,143 @@
+package cloudlogging
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/option"
+	logpb "google.golang.org/genproto/googleapis/logging/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeServer is an in-memory Cloud Logging backend recording the entries it
+// receives, other than the diagnostic entry the client adds. Like the real
+// service, it applies the request's log name, resource and labels to them.
+type fakeServer struct {
+	logpb.UnimplementedLoggingServiceV2Server
+
+	mu      sync.Mutex
+	entries []*logpb.LogEntry
+	// block, when set, holds every write until it is closed.
+	block chan struct{}
+	// fail, when set, is returned by every write.
+	fail error
+}
+
+func (s *fakeServer) WriteLogEntries(ctx context.Context, req *logpb.WriteLogEntriesRequest) (*logpb.WriteLogEntriesResponse, error) {
+	if s.block != nil {
+		select {
+		case <-s.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if s.fail != nil {
+		return nil, s.fail
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range req.Entries {
+		if entry.LogName == "" {
+			entry.LogName = req.LogName
+		}
+		if entry.Resource == nil {
+			entry.Resource = req.Resource
+		}
+		if len(req.Labels) > 0 && entry.Labels == nil {
+			entry.Labels = make(map[string]string)
+		}
+		for k, v := range req.Labels {
+			if _, ok := entry.Labels[k]; !ok {
+				entry.Labels[k] = v
+			}
+		}
+		if !strings.HasSuffix(entry.LogName, "/diagnostic-log") {
+			s.entries = append(s.entries, entry)
+		}
+	}
+	return &logpb.WriteLogEntriesResponse{}, nil
+}
+
+// received returns the entries received so far.
+func (s *fakeServer) received() []*logpb.LogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*logpb.LogEntry(nil), s.entries...)
+}
+
+// total returns the number of entries received.
+func (s *fakeServer) total() int {
+	return len(s.received())
+}
+
+// serve starts s and returns the option pointing a logger's client at it.
+func (s *fakeServer) serve(t testing.TB) Option {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	logpb.RegisterLoggingServiceV2Server(server, s)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	dial := func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}
+	return func(l *Logger) {
+		l.clientOptions = []option.ClientOption{
+			option.WithEndpoint("bufnet"),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithContextDialer(dial)),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		}
+	}
+}
+
+// newServedLogger returns a logger whose client writes to s, with its backup
+// lines written to out.
+func newServedLogger(t testing.TB, s *fakeServer, out io.Writer, opts ...Option) *Logger {
+	opts = append([]Option{s.serve(t)}, opts...)
+	l, err := New(context.Background(), "test-project", "test", log.New(out, "", 0), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestCloseTwice(t *testing.T) {
+	srv := &fakeServer{}
+	l := newServedLogger(t, srv, io.Discard)
+	l.Info("before close")
+
+	var wg sync.WaitGroup
+	results := make([]CloseResult, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			results[i], err = l.CloseStats()
+			if err != nil {
+				t.Errorf("close %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if result != results[0] {
+			t.Errorf("close %d returned %+v, want %+v like the first", i, result, results[0])
+		}
+	}
+	if got := srv.total(); got != 1 {
+		t.Errorf("server received %d entries, want 1", got)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("close after close: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package cloudlogging
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// CloseResult describes the final flush performed by CloseStats.
+type CloseResult struct {
+	// Flushed is the number of entries delivered by the final flush, that
+	// is those logged since the last successful flush by FlushCtx,
+	// WithFlushOnSeverity or WithDeliveryWatchdog. Entries the client sent on
+	// its own in between are counted too, as they cannot be told apart.
+	Flushed int64
+	// Dropped is the number of entries that were pending when the final
+	// flush failed. The client reports failures per batch, so a failed
+	// flush counts every pending entry as dropped.
+	Dropped  int64
+	Duration time.Duration
+}
+
+type CloseMode int
+
+const (
+	// CloseFlush makes Close wait until pending entries are delivered.
+	CloseFlush CloseMode = iota
+	// CloseDiscard makes Close return without waiting for delivery. Pending
+	// entries are lost unless the client manages to send them before the
+	// process exits; CloseStats reports them as dropped.
+	CloseDiscard
+)
+
+// WithCloseMode sets whether Close flushes or discards pending entries.
+// Discarding suits fast-fail shutdowns where waiting risks being killed by the
+// orchestrator anyway, at the cost of losing the last entries.
+func WithCloseMode(mode CloseMode) Option {
+	return func(l *Logger) {
+		l.closeMode = mode
+	}
+}
+
+// WithCloseMessage makes Close log msg at Info before closing, marking the
+// end of the process's log stream. The entry is written synchronously, or to
+// the backup logger when the client is no longer usable.
+func WithCloseMessage(msg string, details ...string) Option {
+	return func(l *Logger) {
+		l.closeMessage = append([]string{msg}, details...)
+	}
+}
+
+// Close stops the background goroutines started by options, flushes pending
+// entries and closes the underlying client. Only the first call does so;
+// later and concurrent calls wait for it and return its result.
+func (l *Logger) Close() error {
+	_, err := l.CloseStats()
+	return err
+}
+
+// CloseWithContext is like Close but stops waiting for background goroutines
+// once ctx is done. The client is closed either way.
+func (l *Logger) CloseWithContext(ctx context.Context) error {
+	_, err := l.closeStats(ctx)
+	return err
+}
+
+// CloseStats is like Close but also reports how many entries were flushed.
+func (l *Logger) CloseStats() (CloseResult, error) {
+	return l.closeStats(context.Background())
+}
+
+func (l *Logger) closeStats(ctx context.Context) (CloseResult, error) {
+	l.closeOnce.Do(func() {
+		l.closeResult, l.closeErr = l.close(ctx)
+	})
+	return l.closeResult, l.closeErr
+}
+
+func (l *Logger) close(ctx context.Context) (CloseResult, error) {
+	l.untrack()
+
+	start := l.now()
+	if l.closeMode == CloseDiscard {
+		if l.closeMessage != nil {
+			l.deliver(l.closeEntry())
+		}
+		return l.discard(start)
+	}
+	if l.closeMessage != nil {
+		l.logCloseMessage(ctx)
+	}
+	waitErr := l.stopBackground(ctx)
+	pending := atomic.SwapInt64(&l.pending, 0)
+
+	var err error
+	switch {
+	case l.conn == nil:
+	case l.child:
+		err = l.flush(l.clientLogger())
+		if subErr := l.flushSubLoggers(); err == nil {
+			err = subErr
+		}
+	default:
+		err = l.conn.close()
+	}
+
+	if l.fileBackup != nil {
+		l.fileBackup.Close()
+	}
+
+	result := CloseResult{Duration: l.now().Sub(start)}
+	if err != nil {
+		result.Dropped = pending
+	} else {
+		result.Flushed = pending
+	}
+	if err == nil {
+		err = waitErr
+	}
+	return result, err
+}
+
+func (l *Logger) discard(start time.Time) (CloseResult, error) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.stopBackground(canceled)
+	pending := atomic.SwapInt64(&l.pending, 0)
+
+	// Only the first Close gets here, so the client is closed once.
+	if l.conn != nil && !l.child {
+		l.conn.discard()
+	}
+	if l.fileBackup != nil {
+		l.fileBackup.Close()
+	}
+	return CloseResult{Dropped: pending, Duration: l.now().Sub(start)}, nil
+}
+
+func (l *Logger) closeEntry() (logging.Entry, map[string]string) {
+	data := payload(l.closeMessage[0], l.closeMessage[1:]...)
+	l.enrich(logging.Info, data)
+	return logging.Entry{Payload: data, Severity: logging.Info}, data
+}
+
+func (l *Logger) logCloseMessage(ctx context.Context) {
+	entry, data := l.closeEntry()
+	if l.conn == nil || isDone(l.systemCtx) {
+		l.deliver(entry, data)
+		return
+	}
+	if err := l.logSync(ctx, l.clientLogger(), entry); err != nil {
+		l.recordError(err, entry.Severity)
+		l.backup.Printf("%-10s: %v", entry.Severity.String(), data)
+	}
+}
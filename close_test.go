@@ -0,0 +1,77 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	ltype "google.golang.org/genproto/googleapis/logging/type"
+	logpb "google.golang.org/genproto/googleapis/logging/v2"
+)
+
+func TestCloseDiscardDoesNotWait(t *testing.T) {
+	srv := &fakeServer{block: make(chan struct{})}
+	l := newServedLogger(t, srv, io.Discard, WithCloseMode(CloseDiscard))
+	t.Cleanup(func() { close(srv.block) })
+	for i := 0; i < 3; i++ {
+		l.Info("pending")
+	}
+
+	done := make(chan CloseResult)
+	go func() {
+		result, err := l.CloseStats()
+		if err != nil {
+			t.Errorf("close: %v", err)
+		}
+		done <- result
+	}()
+	select {
+	case result := <-done:
+		if result.Dropped != 3 || result.Flushed != 0 {
+			t.Errorf("close result = %+v, want 3 dropped", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("close waited for the blocked server")
+	}
+	if got := srv.total(); got != 0 {
+		t.Errorf("server received %d entries before close returned, want 0", got)
+	}
+}
+
+func TestCloseMessage(t *testing.T) {
+	srv := &fakeServer{}
+	l := newServedLogger(t, srv, io.Discard, WithCloseMessage("logger stopped", "reason", "shutdown"))
+	l.Info("last request")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var stopped *logpb.LogEntry
+	for _, entry := range srv.received() {
+		if entry.GetJsonPayload().GetFields()["msg"].GetStringValue() == "logger stopped" {
+			stopped = entry
+		}
+	}
+	if stopped == nil {
+		t.Fatalf("server received %d entries, none the close message", srv.total())
+	}
+	if stopped.Severity != ltype.LogSeverity_INFO {
+		t.Errorf("close message severity = %v, want INFO", stopped.Severity)
+	}
+	if got := stopped.GetJsonPayload().GetFields()["reason"].GetStringValue(); got != "shutdown" {
+		t.Errorf("close message reason = %q, want shutdown", got)
+	}
+	if got := srv.total(); got != 2 {
+		t.Errorf("server received %d entries, want 2", got)
+	}
+}
+
+func TestCloseMessageWithoutClient(t *testing.T) {
+	var out bytes.Buffer
+	l := newTestLogger(&out, WithCloseMessage("logger stopped"))
+	l.Close()
+	if got, want := out.String(), "Info      : map[msg:logger stopped]\n"; got != want {
+		t.Errorf("backup = %q, want %q", got, want)
+	}
+}
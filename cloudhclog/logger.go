@@ -0,0 +1,163 @@
+// Package cloudhclog implements hclog.Logger on top of a cloudlogging.Logger,
+// so HashiCorp libraries such as raft and the Vault and Consul clients log to
+// Cloud Logging.
+package cloudhclog
+
+import (
+	"io"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"cloud.google.com/go/logging"
+	"github.com/hashicorp/go-hclog"
+
+	cloudlogging "github.com/newjar/cloud-logging"
+	"github.com/newjar/cloud-logging/internal/details"
+)
+
+type logger struct {
+	logger *cloudlogging.Logger
+	level  *int32 // hclog.Level, shared with the loggers derived by With and Named
+	name   string
+	args   []interface{}
+}
+
+// New returns an hclog.Logger writing through l the entries at level and
+// above. Trace and Debug are logged at Debug, and Info, Warn and Error at the
+// matching severity. The logger name, with sub-logger names joined by dots,
+// is added as the "logger" detail, and arguments, implied ones first, become
+// details. SetLevel on any logger derived from the result changes the level
+// of all of them, as in hclog.
+func New(l *cloudlogging.Logger, name string, level hclog.Level) hclog.Logger {
+	lvl := int32(level)
+	return &logger{logger: l, level: &lvl, name: name}
+}
+
+func (l *logger) Log(level hclog.Level, msg string, args ...interface{}) {
+	if level == hclog.Off || level < l.GetLevel() {
+		return
+	}
+	b := l.logger.Entry().Severity(severity(level)).Message(msg)
+	if l.name != "" {
+		b.Detail("logger", l.name)
+	}
+	pairs := details.AppendPairs(details.AppendPairs(nil, l.args), args)
+	for i := 0; i < len(pairs); i += 2 {
+		b.Detail(pairs[i], pairs[i+1])
+	}
+	b.Send()
+}
+
+func (l *logger) Trace(msg string, args ...interface{}) { l.Log(hclog.Trace, msg, args...) }
+func (l *logger) Debug(msg string, args ...interface{}) { l.Log(hclog.Debug, msg, args...) }
+func (l *logger) Info(msg string, args ...interface{})  { l.Log(hclog.Info, msg, args...) }
+func (l *logger) Warn(msg string, args ...interface{})  { l.Log(hclog.Warn, msg, args...) }
+func (l *logger) Error(msg string, args ...interface{}) { l.Log(hclog.Error, msg, args...) }
+
+func (l *logger) IsTrace() bool { return l.GetLevel() <= hclog.Trace }
+func (l *logger) IsDebug() bool { return l.GetLevel() <= hclog.Debug }
+func (l *logger) IsInfo() bool  { return l.GetLevel() <= hclog.Info }
+func (l *logger) IsWarn() bool  { return l.GetLevel() <= hclog.Warn }
+func (l *logger) IsError() bool { return l.GetLevel() <= hclog.Error }
+
+func (l *logger) ImpliedArgs() []interface{} {
+	return l.args
+}
+
+func (l *logger) With(args ...interface{}) hclog.Logger {
+	c := *l
+	c.args = append(append([]interface{}(nil), l.args...), args...)
+	return &c
+}
+
+func (l *logger) Name() string {
+	return l.name
+}
+
+func (l *logger) Named(name string) hclog.Logger {
+	c := *l
+	if c.name != "" {
+		c.name += "." + name
+	} else {
+		c.name = name
+	}
+	return &c
+}
+
+func (l *logger) ResetNamed(name string) hclog.Logger {
+	c := *l
+	c.name = name
+	return &c
+}
+
+func (l *logger) SetLevel(level hclog.Level) {
+	atomic.StoreInt32(l.level, int32(level))
+}
+
+func (l *logger) GetLevel() hclog.Level {
+	return hclog.Level(atomic.LoadInt32(l.level))
+}
+
+func (l *logger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return log.New(l.StandardWriter(opts), "", 0)
+}
+
+// StandardWriter returns a writer logging each write at opts.ForceLevel, or
+// at the level in a leading "[ERROR]"-style tag with opts.InferLevels, or at
+// Info.
+func (l *logger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	if opts == nil {
+		opts = &hclog.StandardLoggerOptions{}
+	}
+	return &writer{logger: l, opts: *opts}
+}
+
+type writer struct {
+	logger *logger
+	opts   hclog.StandardLoggerOptions
+}
+
+var levelTags = []struct {
+	tag   string
+	level hclog.Level
+}{
+	{"[TRACE]", hclog.Trace},
+	{"[DEBUG]", hclog.Debug},
+	{"[INFO]", hclog.Info},
+	{"[WARN]", hclog.Warn},
+	{"[ERROR]", hclog.Error},
+	{"[ERR]", hclog.Error},
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	level := hclog.Info
+	if w.opts.ForceLevel != hclog.NoLevel {
+		level = w.opts.ForceLevel
+	} else if w.opts.InferLevels {
+		for _, t := range levelTags {
+			if strings.HasPrefix(msg, t.tag) {
+				level = t.level
+				msg = strings.TrimSpace(strings.TrimPrefix(msg, t.tag))
+				break
+			}
+		}
+	}
+	w.logger.Log(level, msg)
+	return len(p), nil
+}
+
+func severity(level hclog.Level) logging.Severity {
+	switch level {
+	case hclog.Trace, hclog.Debug:
+		return logging.Debug
+	case hclog.Info:
+		return logging.Info
+	case hclog.Warn:
+		return logging.Warning
+	case hclog.Error:
+		return logging.Error
+	}
+	return logging.Default
+}
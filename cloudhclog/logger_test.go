@@ -0,0 +1,121 @@
+package cloudhclog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+	"github.com/hashicorp/go-hclog"
+
+	cloudlogging "github.com/newjar/cloud-logging"
+)
+
+func newLogger(t *testing.T) *cloudlogging.Logger {
+	l, err := cloudlogging.New(context.Background(), "test-project", "test", log.New(io.Discard, "", 0),
+		cloudlogging.WithLogfmtSink(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestLogger(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		log      func(hclog.Logger)
+		severity logging.Severity
+		details  map[string]string
+	}{
+		{"trace", func(h hclog.Logger) { h.Trace("elected") }, logging.Debug, map[string]string{"logger": "raft"}},
+		{"debug", func(h hclog.Logger) { h.Debug("elected") }, logging.Debug, map[string]string{"logger": "raft"}},
+		{"info", func(h hclog.Logger) { h.Info("elected") }, logging.Info, map[string]string{"logger": "raft"}},
+		{"warn", func(h hclog.Logger) { h.Warn("elected") }, logging.Warning, map[string]string{"logger": "raft"}},
+		{"error", func(h hclog.Logger) { h.Error("elected") }, logging.Error, map[string]string{"logger": "raft"}},
+		{
+			"values",
+			func(h hclog.Logger) {
+				h.Info("elected", "term", 7, "took", 1500*time.Millisecond, "error", errors.New("stale vote"), "peers", []string{"a", "b"}, "dangling")
+			},
+			logging.Info, map[string]string{"logger": "raft", "term": "7", "took": "1.5s", "error": "stale vote", "peers": `["a","b"]`, "dangling": "MISSING"},
+		},
+		{
+			"with and named",
+			func(h hclog.Logger) {
+				h = h.Named("fsm").With("node", "n1")
+				h.Named("snapshot").With("index", 42).Info("elected", "term", 7)
+			},
+			logging.Info, map[string]string{"logger": "raft.fsm.snapshot", "node": "n1", "index": "42", "term": "7"},
+		},
+		{
+			"reset named",
+			func(h hclog.Logger) { h.Named("fsm").ResetNamed("store").Info("elected") },
+			logging.Info, map[string]string{"logger": "store"},
+		},
+		{
+			"with does not leak",
+			func(h hclog.Logger) {
+				h.With("node", "n1")
+				h.Info("elected")
+			},
+			logging.Info, map[string]string{"logger": "raft"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLogger(t)
+			entries := l.Capture(func() { tt.log(New(l, "raft", hclog.Trace)) })
+			want := []cloudlogging.Entry{{Severity: tt.severity, Message: "elected", Details: tt.details, Labels: map[string]string{}}}
+			if !reflect.DeepEqual(entries, want) {
+				t.Errorf("entries = %+v\nwant %+v", entries, want)
+			}
+		})
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	l := newLogger(t)
+	h := New(l, "", hclog.Warn)
+	child := h.Named("fsm")
+	entries := l.Capture(func() {
+		h.Info("filtered")
+		h.Warn("kept")
+		child.SetLevel(hclog.Error)
+		h.Warn("filtered after SetLevel on a sub-logger")
+		h.Error("kept after SetLevel")
+	})
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Message)
+	}
+	if want := []string{"kept", "kept after SetLevel"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("logged %q, want %q", got, want)
+	}
+}
+
+func TestStandardWriter(t *testing.T) {
+	l := newLogger(t)
+	h := New(l, "", hclog.Trace)
+	entries := l.Capture(func() {
+		h.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true}).Print("[WARN] disk almost full")
+		h.StandardLogger(&hclog.StandardLoggerOptions{ForceLevel: hclog.Error}).Print("[WARN] forced")
+		h.StandardLogger(nil).Print("plain")
+	})
+
+	type logged struct {
+		severity logging.Severity
+		message  string
+	}
+	var got []logged
+	for _, e := range entries {
+		got = append(got, logged{e.Severity, e.Message})
+	}
+	want := []logged{{logging.Warning, "disk almost full"}, {logging.Error, "[WARN] forced"}, {logging.Info, "plain"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("logged %v, want %v", got, want)
+	}
+}
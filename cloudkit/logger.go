@@ -0,0 +1,58 @@
+// Package cloudkit adapts cloudlogging.Logger to go-kit's log.Logger
+// interface. It has no dependency on go-kit: the interface is a single Log
+// method, satisfied structurally.
+package cloudkit
+
+import (
+	"strings"
+
+	"cloud.google.com/go/logging"
+
+	cloudlogging "github.com/newjar/cloud-logging"
+	"github.com/newjar/cloud-logging/internal/details"
+)
+
+// Logger logs go-kit keyvals through a cloudlogging.Logger. The "msg" keyval
+// is the message and the "level" keyval, as set by go-kit's level package,
+// the severity; entries without one are logged at Default. The other keyvals
+// become details.
+type Logger struct {
+	logger *cloudlogging.Logger
+}
+
+func NewLogger(logger *cloudlogging.Logger) *Logger {
+	return &Logger{logger: logger}
+}
+
+func (l *Logger) Log(keyvals ...interface{}) error {
+	b := l.logger.Entry().Severity(logging.Default)
+	pairs := details.AppendPairs(nil, keyvals)
+	for i := 0; i < len(pairs); i += 2 {
+		switch key, value := pairs[i], pairs[i+1]; key {
+		case "msg":
+			b.Message(value)
+		case "level":
+			b.Severity(severity(value))
+		default:
+			b.Detail(key, value)
+		}
+	}
+	b.Send()
+	return nil
+}
+
+// severity maps the go-kit level names, and the Cloud Logging ones, to a
+// severity.
+func severity(level string) logging.Severity {
+	switch strings.ToLower(level) {
+	case "debug":
+		return logging.Debug
+	case "info":
+		return logging.Info
+	case "warn":
+		return logging.Warning
+	case "error":
+		return logging.Error
+	}
+	return logging.ParseSeverity(level)
+}
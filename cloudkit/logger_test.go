@@ -0,0 +1,85 @@
+package cloudkit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+
+	cloudlogging "github.com/newjar/cloud-logging"
+)
+
+func newLogger(t *testing.T) *cloudlogging.Logger {
+	l, err := cloudlogging.New(context.Background(), "test-project", "test", log.New(io.Discard, "", 0),
+		cloudlogging.WithLogfmtSink(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+// level mimics the values of go-kit's level package, which are fmt.Stringers.
+type level string
+
+func (l level) String() string { return string(l) }
+
+// with mimics go-kit's log.With, which prepends keyvals to every call.
+type with struct {
+	next    interface{ Log(...interface{}) error }
+	keyvals []interface{}
+}
+
+func (w with) Log(keyvals ...interface{}) error {
+	return w.next.Log(append(append([]interface{}(nil), w.keyvals...), keyvals...)...)
+}
+
+func TestLogger(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		keyvals  []interface{}
+		severity logging.Severity
+		details  map[string]string
+	}{
+		{"no level", []interface{}{"msg", "served"}, logging.Default, map[string]string{}},
+		{"debug", []interface{}{"level", level("debug"), "msg", "served"}, logging.Debug, map[string]string{}},
+		{"info", []interface{}{"level", level("info"), "msg", "served"}, logging.Info, map[string]string{}},
+		{"warn", []interface{}{"level", level("warn"), "msg", "served"}, logging.Warning, map[string]string{}},
+		{"error", []interface{}{"level", level("error"), "msg", "served"}, logging.Error, map[string]string{}},
+		{"cloud severity", []interface{}{"level", "CRITICAL", "msg", "served"}, logging.Critical, map[string]string{}},
+		{
+			"values",
+			[]interface{}{"msg", "served", "status", 200, "took", 1500 * time.Millisecond, "err", errors.New("cache miss"), "tags", []string{"a"}, 42, "numeric key", "dangling"},
+			logging.Default, map[string]string{"status": "200", "took": "1.5s", "err": "cache miss", "tags": `["a"]`, "42": "numeric key", "dangling": "MISSING"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLogger(t)
+			entries := l.Capture(func() { NewLogger(l).Log(tt.keyvals...) })
+			want := []cloudlogging.Entry{{Severity: tt.severity, Message: "served", Details: tt.details, Labels: map[string]string{}}}
+			if !reflect.DeepEqual(entries, want) {
+				t.Errorf("entries = %+v\nwant %+v", entries, want)
+			}
+		})
+	}
+}
+
+func TestLoggerWith(t *testing.T) {
+	l := newLogger(t)
+	kit := with{next: with{next: NewLogger(l), keyvals: []interface{}{"service", "billing"}}, keyvals: []interface{}{"level", level("warn")}}
+	entries := l.Capture(func() { kit.Log("msg", "served", "route", "/pay") })
+
+	want := []cloudlogging.Entry{{
+		Severity: logging.Warning,
+		Message:  "served",
+		Details:  map[string]string{"service": "billing", "route": "/pay"},
+		Labels:   map[string]string{},
+	}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v\nwant %+v", entries, want)
+	}
+}
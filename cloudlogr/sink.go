@@ -0,0 +1,81 @@
+// Package cloudlogr implements logr.LogSink on top of a cloudlogging.Logger,
+// for controller-runtime and other logr consumers.
+package cloudlogr
+
+import (
+	"cloud.google.com/go/logging"
+	"github.com/go-logr/logr"
+
+	cloudlogging "github.com/newjar/cloud-logging"
+	"github.com/newjar/cloud-logging/internal/details"
+)
+
+type sink struct {
+	logger  *cloudlogging.Logger
+	maxV    int
+	name    string
+	details []string
+}
+
+// NewLogger returns a logr.Logger writing through logger.
+func NewLogger(logger *cloudlogging.Logger, maxV int) logr.Logger {
+	return logr.New(NewLogSink(logger, maxV))
+}
+
+// NewLogSink returns a logr.LogSink writing through logger. Info at V(0) is
+// logged at Info and at higher verbosities, up to maxV, at Debug; Error is
+// logged at Error with the error's message as the "error" detail. Names are
+// joined with "/" into the "logger" detail, and key/value pairs become
+// details.
+func NewLogSink(logger *cloudlogging.Logger, maxV int) logr.LogSink {
+	return &sink{logger: logger, maxV: maxV}
+}
+
+func (s *sink) Init(logr.RuntimeInfo) {}
+
+func (s *sink) Enabled(level int) bool {
+	return level <= s.maxV
+}
+
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	severity := logging.Info
+	if level > 0 {
+		severity = logging.Debug
+	}
+	s.send(severity, msg, nil, keysAndValues)
+}
+
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.send(logging.Error, msg, err, keysAndValues)
+}
+
+func (s *sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	c := *s
+	c.details = details.AppendPairs(append([]string(nil), s.details...), keysAndValues)
+	return &c
+}
+
+func (s *sink) WithName(name string) logr.LogSink {
+	c := *s
+	if c.name == "" {
+		c.name = name
+	} else {
+		c.name = s.name + "/" + name
+	}
+	return &c
+}
+
+func (s *sink) send(severity logging.Severity, msg string, err error, keysAndValues []interface{}) {
+	b := s.logger.Entry().Severity(severity).Message(msg)
+	if s.name != "" {
+		b.Detail("logger", s.name)
+	}
+	pairs := details.AppendPairs(append([]string(nil), s.details...), keysAndValues)
+	for i := 0; i < len(pairs); i += 2 {
+		b.Detail(pairs[i], pairs[i+1])
+	}
+	if err != nil {
+		b.Detail("error", err.Error())
+	}
+	b.Send()
+}
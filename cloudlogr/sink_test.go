@@ -0,0 +1,86 @@
+package cloudlogr
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+	"github.com/go-logr/logr"
+
+	cloudlogging "github.com/newjar/cloud-logging"
+)
+
+func newLogger(t *testing.T) *cloudlogging.Logger {
+	l, err := cloudlogging.New(context.Background(), "test-project", "test", log.New(io.Discard, "", 0),
+		cloudlogging.WithLogfmtSink(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestLogSink(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		log      func(logr.Logger)
+		severity logging.Severity
+		details  map[string]string
+	}{
+		{"info", func(lg logr.Logger) { lg.Info("reconciled") }, logging.Info, map[string]string{}},
+		{"verbose", func(lg logr.Logger) { lg.V(2).Info("reconciled") }, logging.Debug, map[string]string{}},
+		{
+			"error",
+			func(lg logr.Logger) { lg.Error(errors.New("conflict"), "reconciled", "retries", 3) },
+			logging.Error, map[string]string{"error": "conflict", "retries": "3"},
+		},
+		{
+			"values",
+			func(lg logr.Logger) {
+				lg.Info("reconciled", "took", 1500*time.Millisecond, "ready", true, "owner", map[string]string{"kind": "Deployment"}, "dangling")
+			},
+			logging.Info, map[string]string{"took": "1.5s", "ready": "true", "owner": `{"kind":"Deployment"}`, "dangling": "MISSING"},
+		},
+		{
+			"names and values",
+			func(lg logr.Logger) {
+				lg = lg.WithName("controller").WithValues("namespace", "prod")
+				lg.WithName("deployment").WithValues("name", "api").Info("reconciled")
+			},
+			logging.Info, map[string]string{"logger": "controller/deployment", "namespace": "prod", "name": "api"},
+		},
+		{
+			"values do not leak",
+			func(lg logr.Logger) {
+				lg.WithName("controller").WithValues("namespace", "prod")
+				lg.Info("reconciled")
+			},
+			logging.Info, map[string]string{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLogger(t)
+			entries := l.Capture(func() { tt.log(NewLogger(l, 2)) })
+			want := []cloudlogging.Entry{{Severity: tt.severity, Message: "reconciled", Details: tt.details, Labels: map[string]string{}}}
+			if !reflect.DeepEqual(entries, want) {
+				t.Errorf("entries = %+v\nwant %+v", entries, want)
+			}
+		})
+	}
+}
+
+func TestLogSinkVerbosity(t *testing.T) {
+	l := newLogger(t)
+	lg := NewLogger(l, 1)
+	entries := l.Capture(func() {
+		lg.V(1).Info("kept")
+		lg.V(2).Info("filtered")
+	})
+	if len(entries) != 1 || entries[0].Message != "kept" {
+		t.Errorf("entries = %+v, want only the V(1) entry", entries)
+	}
+}
@@ -0,0 +1,69 @@
+// Package cloudlogrus forwards logrus entries to a cloudlogging.Logger.
+package cloudlogrus
+
+import (
+	"context"
+	"strconv"
+
+	"cloud.google.com/go/logging"
+	"github.com/sirupsen/logrus"
+
+	cloudlogging "github.com/newjar/cloud-logging"
+	"github.com/newjar/cloud-logging/internal/details"
+)
+
+// Hook is a logrus.Hook logging every entry it fires for through a
+// cloudlogging.Logger, and so to its backup logger when Cloud Logging is not
+// usable.
+type Hook struct {
+	logger *cloudlogging.Logger
+	levels []logrus.Level
+}
+
+// NewHook returns a hook firing for levels, or for all levels when none are
+// given. Add it with logrus.AddHook. Fields become details, error values
+// included, and the caller, when reported, the "caller" detail.
+func NewHook(logger *cloudlogging.Logger, levels ...logrus.Level) *Hook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+	return &Hook{logger: logger, levels: levels}
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *Hook) Fire(e *logrus.Entry) error {
+	b := h.logger.Entry().Severity(severity(e.Level)).Message(e.Message).At(e.Time)
+	for k, v := range e.Data {
+		b.Detail(k, details.String(v))
+	}
+	if e.Caller != nil {
+		b.Detail("caller", e.Caller.File+":"+strconv.Itoa(e.Caller.Line))
+	}
+	b.Send()
+	if e.Level <= logrus.FatalLevel {
+		// logrus exits or panics right after the hooks for these levels,
+		// before the client would send the entry.
+		return h.logger.FlushCtx(context.Background())
+	}
+	return nil
+}
+
+func severity(level logrus.Level) logging.Severity {
+	switch level {
+	case logrus.PanicLevel:
+		return logging.Alert
+	case logrus.FatalLevel:
+		return logging.Emergency
+	case logrus.ErrorLevel:
+		return logging.Error
+	case logrus.WarnLevel:
+		return logging.Warning
+	case logrus.InfoLevel:
+		return logging.Info
+	default:
+		return logging.Debug
+	}
+}
@@ -0,0 +1,107 @@
+package cloudlogrus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+	"github.com/sirupsen/logrus"
+
+	cloudlogging "github.com/newjar/cloud-logging"
+)
+
+func newLogger(t *testing.T) *cloudlogging.Logger {
+	l, err := cloudlogging.New(context.Background(), "test-project", "test", log.New(io.Discard, "", 0),
+		cloudlogging.WithLogfmtSink(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestHookLevels(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		level logrus.Level
+		want  logging.Severity
+	}{
+		{logrus.TraceLevel, logging.Debug},
+		{logrus.DebugLevel, logging.Debug},
+		{logrus.InfoLevel, logging.Info},
+		{logrus.WarnLevel, logging.Warning},
+		{logrus.ErrorLevel, logging.Error},
+		{logrus.FatalLevel, logging.Emergency},
+		{logrus.PanicLevel, logging.Alert},
+	} {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			l := newLogger(t)
+			entries := l.Capture(func() {
+				e := &logrus.Entry{Level: tt.level, Message: "served", Time: at, Data: logrus.Fields{}}
+				if err := NewHook(l).Fire(e); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if len(entries) != 1 || entries[0].Severity != tt.want || !entries[0].Timestamp.Equal(at) {
+				t.Errorf("entries = %+v, want one %v entry at %v", entries, tt.want, at)
+			}
+		})
+	}
+}
+
+func TestHookDetails(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		log  func(*logrus.Logger)
+		want map[string]string
+	}{
+		{
+			"fields",
+			func(lg *logrus.Logger) {
+				lg.WithFields(logrus.Fields{"status": 200, "took": 1500 * time.Millisecond, "tags": []string{"a", "b"}}).
+					WithError(errors.New("cache miss")).Info("served")
+			},
+			map[string]string{"status": "200", "took": "1.5s", "tags": `["a","b"]`, "error": "cache miss"},
+		},
+		{
+			"accumulated",
+			func(lg *logrus.Logger) {
+				base := lg.WithField("service", "billing")
+				base.WithField("region", "eu").WithField("route", "/pay").Info("served")
+			},
+			map[string]string{"service": "billing", "region": "eu", "route": "/pay"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLogger(t)
+			lg := logrus.New()
+			lg.Out = io.Discard
+			lg.AddHook(NewHook(l))
+			entries := l.Capture(func() { tt.log(lg) })
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+			if got := entries[0].Details; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("details = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookSelectedLevels(t *testing.T) {
+	l := newLogger(t)
+	lg := logrus.New()
+	lg.Out = io.Discard
+	lg.AddHook(NewHook(l, logrus.ErrorLevel, logrus.WarnLevel))
+	entries := l.Capture(func() {
+		lg.Info("filtered")
+		lg.Warn("kept")
+	})
+	if len(entries) != 1 || entries[0].Message != "kept" {
+		t.Errorf("entries = %+v, want only the Warn entry", entries)
+	}
+}
@@ -0,0 +1,111 @@
+// Package cloudzap adapts cloudlogging.Logger to zap, so services using zap
+// ship their entries to Cloud Logging without a second configuration.
+package cloudzap
+
+import (
+	"context"
+
+	"cloud.google.com/go/logging"
+	"go.uber.org/zap/zapcore"
+
+	cloudlogging "github.com/newjar/cloud-logging"
+	"github.com/newjar/cloud-logging/internal/details"
+)
+
+type core struct {
+	zapcore.LevelEnabler
+	logger  *cloudlogging.Logger
+	details []string
+}
+
+// NewCore returns a zapcore.Core writing through logger the entries enabled
+// by enab. Fields become details, with non-string values encoded as JSON.
+// Zap levels map to severities: Debug, Info, Warning and Error as named,
+// DPanic to Critical, Panic to Alert and Fatal to Emergency. The entry's
+// logger name, caller and stack are added as the "logger", "caller" and
+// "stack_trace" details when set.
+func NewCore(logger *cloudlogging.Logger, enab zapcore.LevelEnabler) zapcore.Core {
+	return &core{LevelEnabler: enab, logger: logger}
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{
+		LevelEnabler: c.LevelEnabler,
+		logger:       c.logger,
+		details:      appendFields(append([]string(nil), c.details...), fields),
+	}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	pairs := appendFields(append([]string(nil), c.details...), fields)
+	if ent.LoggerName != "" {
+		pairs = append(pairs, "logger", ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		pairs = append(pairs, "caller", ent.Caller.String())
+	}
+	if ent.Stack != "" {
+		pairs = append(pairs, "stack_trace", ent.Stack)
+	}
+
+	b := c.logger.Entry().Severity(severity(ent.Level)).Message(ent.Message).At(ent.Time)
+	for i := 0; i < len(pairs); i += 2 {
+		b.Detail(pairs[i], pairs[i+1])
+	}
+	b.Send()
+	if ent.Level > zapcore.ErrorLevel {
+		// DPanic, Panic and Fatal may end the process before the client
+		// sends the entry.
+		return c.Sync()
+	}
+	return nil
+}
+
+func (c *core) Sync() error {
+	return c.logger.FlushCtx(context.Background())
+}
+
+// appendFields appends fields to dst as key/value details.
+func appendFields(dst []string, fields []zapcore.Field) []string {
+	if len(fields) == 0 {
+		return dst
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	for k, v := range enc.Fields {
+		dst = append(dst, k, details.String(v))
+	}
+	return dst
+}
+
+func severity(level zapcore.Level) logging.Severity {
+	switch level {
+	case zapcore.DebugLevel:
+		return logging.Debug
+	case zapcore.InfoLevel:
+		return logging.Info
+	case zapcore.WarnLevel:
+		return logging.Warning
+	case zapcore.ErrorLevel:
+		return logging.Error
+	case zapcore.DPanicLevel:
+		return logging.Critical
+	case zapcore.PanicLevel:
+		return logging.Alert
+	case zapcore.FatalLevel:
+		return logging.Emergency
+	}
+	if level < zapcore.DebugLevel {
+		return logging.Debug
+	}
+	return logging.Emergency
+}
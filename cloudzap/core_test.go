@@ -0,0 +1,117 @@
+package cloudzap
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	cloudlogging "github.com/newjar/cloud-logging"
+)
+
+func newLogger(t *testing.T) *cloudlogging.Logger {
+	l, err := cloudlogging.New(context.Background(), "test-project", "test", log.New(io.Discard, "", 0),
+		cloudlogging.WithLogfmtSink(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestCoreLevels(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		level zapcore.Level
+		want  logging.Severity
+	}{
+		{zapcore.DebugLevel - 1, logging.Debug},
+		{zapcore.DebugLevel, logging.Debug},
+		{zapcore.InfoLevel, logging.Info},
+		{zapcore.WarnLevel, logging.Warning},
+		{zapcore.ErrorLevel, logging.Error},
+		{zapcore.DPanicLevel, logging.Critical},
+		{zapcore.PanicLevel, logging.Alert},
+		{zapcore.FatalLevel, logging.Emergency},
+	} {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			l := newLogger(t)
+			c := NewCore(l, zapcore.DebugLevel-1)
+			entries := l.Capture(func() {
+				if err := c.Write(zapcore.Entry{Level: tt.level, Message: "served", Time: at}, nil); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if len(entries) != 1 || entries[0].Severity != tt.want || !entries[0].Timestamp.Equal(at) {
+				t.Errorf("entries = %+v, want one %v entry at %v", entries, tt.want, at)
+			}
+		})
+	}
+}
+
+func TestCoreDetails(t *testing.T) {
+	type order struct {
+		ID    string `json:"id"`
+		Items int    `json:"items"`
+	}
+	for _, tt := range []struct {
+		name string
+		log  func(*zap.Logger)
+		want map[string]string
+	}{
+		{
+			"fields",
+			func(z *zap.Logger) {
+				z.Info("served", zap.Int("status", 200), zap.Duration("took", 1500*time.Millisecond),
+					zap.Error(errors.New("cache miss")), zap.Any("order", order{"o-1", 3}))
+			},
+			map[string]string{"status": "200", "took": "1.5s", "error": "cache miss", "order": `{"id":"o-1","items":3}`},
+		},
+		{
+			"with and named",
+			func(z *zap.Logger) {
+				z = z.Named("api").With(zap.String("service", "billing"))
+				z = z.Named("v1").With(zap.String("region", "eu"))
+				z.Info("served", zap.String("route", "/pay"))
+			},
+			map[string]string{"service": "billing", "region": "eu", "route": "/pay", "logger": "api.v1"},
+		},
+		{
+			"with does not leak",
+			func(z *zap.Logger) {
+				z.With(zap.String("service", "billing"))
+				z.Info("served")
+			},
+			map[string]string{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLogger(t)
+			entries := l.Capture(func() { tt.log(zap.New(NewCore(l, zapcore.DebugLevel))) })
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+			if got := entries[0].Details; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("details = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoreLevelEnabler(t *testing.T) {
+	l := newLogger(t)
+	z := zap.New(NewCore(l, zapcore.WarnLevel))
+	entries := l.Capture(func() {
+		z.Info("filtered")
+		z.Warn("kept")
+	})
+	if len(entries) != 1 || entries[0].Message != "kept" {
+		t.Errorf("entries = %+v, want only the Warn entry", entries)
+	}
+}
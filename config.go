@@ -0,0 +1,168 @@
+package cloudlogging
+
+import (
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// Config is the effective configuration of a logger, after defaults and
+// options are applied. Options taking functions are reported as enabled or
+// not.
+type Config struct {
+	MinSeverity  logging.Severity
+	CommonLabels map[string]string
+	// Fields are the fields added to every entry, such as those of
+	// WithBuildInfo and WithHostMetadata.
+	Fields       map[string]string
+	SchemaFields []string
+	KeyPrefix    string
+	KeyCase      KeyCase
+
+	PromotedLabels []string
+	ResourceType   string
+
+	SeverityFromDetail string
+	SchemaVersion      string
+	RequestIDLabel     string
+	DeadlineField      bool
+	RequireMessage     bool
+	MessagePlaceholder string
+	SanitizeUTF8       bool
+
+	StdoutJSON         bool
+	LogfmtSink         bool
+	SeverityKey        string
+	SeverityFormat     bool
+	JSONEncoder        bool
+	PostCancelWriter   bool
+	ConsoleEcho        bool
+	ConsoleSeverity    logging.Severity
+	LoggerNameInBackup bool
+
+	JSONLineSeverityKey string
+	JSONLineMessageKey  string
+
+	CloseMode    CloseMode
+	CloseMessage string
+
+	MinimalMode         bool
+	GoroutineID         bool
+	StackTrace          bool
+	MaxLabelValueLength int
+	ChunkFields         int
+	DeliveryMetrics     bool
+	ErrorHistory        int
+	StatusSeverity      bool
+	SeverityComparator  bool
+	ModeChangeHook      bool
+
+	SeverityLogNames    map[logging.Severity]string
+	DynamicLogNameLimit int
+	SeverityHooks       int
+	FlushOnSeverity     bool
+	FlushSeverity       logging.Severity
+	FlushBufferSize     int
+
+	HeartbeatInterval    time.Duration
+	RuntimeStatsInterval time.Duration
+	DeliveryWatchdog     time.Duration
+	PauseBufferSize      int
+
+	SpikeLimit  int
+	SpikeWindow time.Duration
+
+	ErrorThrottle   time.Duration
+	DefaultSeverity logging.Severity
+
+	DampenThreshold int
+	DampenWindow    time.Duration
+	DampenTo        logging.Severity
+
+	RegistryTracking bool
+	TrackThreshold   int
+}
+
+// Config returns a copy of the logger's effective configuration.
+func (l *Logger) Config() Config {
+	s := l.settings.clone()
+	c := Config{
+		MinSeverity:  s.minSeverity,
+		CommonLabels: s.commonLabels,
+		Fields:       s.fields,
+		SchemaFields: s.schemaFields,
+		KeyPrefix:    s.keyPrefix,
+		KeyCase:      s.keyCase,
+
+		PromotedLabels: s.promoteLabels,
+
+		SeverityFromDetail: s.severityDetail,
+		SchemaVersion:      s.schemaVersion,
+		RequestIDLabel:     s.requestIDLabel,
+		DeadlineField:      s.deadlineField,
+		RequireMessage:     s.requireMessage,
+		MessagePlaceholder: s.msgPlaceholder,
+		SanitizeUTF8:       s.sanitizeUTF8,
+
+		StdoutJSON:         s.stdout != nil,
+		LogfmtSink:         s.logfmt != nil,
+		SeverityKey:        jsonSeverityKey,
+		SeverityFormat:     s.severityFormat != nil,
+		JSONEncoder:        s.jsonEncoder != nil,
+		PostCancelWriter:   s.postCancel != nil,
+		ConsoleEcho:        s.console != nil,
+		ConsoleSeverity:    s.consoleSeverity,
+		LoggerNameInBackup: s.nameInBackup,
+
+		JSONLineSeverityKey: s.jsonLineSeverityKey,
+		JSONLineMessageKey:  s.jsonLineMessageKey,
+
+		CloseMode: s.closeMode,
+
+		MinimalMode:         s.minimal,
+		GoroutineID:         s.goroutineID,
+		StackTrace:          s.stackFormatter != nil,
+		MaxLabelValueLength: s.maxLabelValueLength,
+		ChunkFields:         s.chunkFields,
+		DeliveryMetrics:     s.deliveryMetrics,
+		ErrorHistory:        s.errorHistorySize,
+		StatusSeverity:      s.statusSeverity != nil,
+		SeverityComparator:  s.compareSeverity != nil,
+		ModeChangeHook:      s.modeHook != nil,
+
+		SeverityLogNames:    s.severityLogNames,
+		DynamicLogNameLimit: s.dynamicLogLimit,
+		SeverityHooks:       len(s.severityHooks),
+		FlushOnSeverity:     s.flushOnSeverity,
+		FlushSeverity:       s.flushSeverity,
+		FlushBufferSize:     s.flushBufferSize,
+
+		HeartbeatInterval:    s.heartbeatInterval,
+		RuntimeStatsInterval: s.runtimeStatsInterval,
+		DeliveryWatchdog:     s.watchdogWindow,
+		PauseBufferSize:      s.pauseBufferSize,
+
+		SpikeLimit:  s.spikeLimit,
+		SpikeWindow: s.spikeWindow,
+
+		ErrorThrottle:   s.throttleInterval,
+		DefaultSeverity: s.defaultSeverity,
+
+		DampenThreshold: s.dampenThreshold,
+		DampenWindow:    s.dampenWindow,
+		DampenTo:        s.dampenTo,
+
+		RegistryTracking: s.tracked,
+		TrackThreshold:   s.trackThreshold,
+	}
+	if s.resource != nil {
+		c.ResourceType = s.resource.Type
+	}
+	if s.severityKey != "" {
+		c.SeverityKey = s.severityKey
+	}
+	if len(s.closeMessage) > 0 {
+		c.CloseMessage = s.closeMessage[0]
+	}
+	return c
+}
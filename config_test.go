@@ -0,0 +1,44 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestConfig(t *testing.T) {
+	c := newTestLogger(io.Discard).Config()
+	if c.MinSeverity != logging.Default || c.SeverityKey != "severity" || c.CloseMode != CloseFlush ||
+		c.StdoutJSON || c.DeliveryWatchdog != 0 || !c.SanitizeUTF8 ||
+		c.MaxLabelValueLength != defaultMaxLabelValueLength || len(c.CommonLabels) != 0 {
+		t.Errorf("default config = %+v", c)
+	}
+
+	l := newTestLogger(io.Discard,
+		WithMinSeverity(logging.Warning),
+		WithLabels("team", "billing"),
+		WithStdoutJSON(),
+		WithSeverityKey("level"),
+		WithCloseMessage("logger stopped"),
+		WithDeliveryWatchdog(time.Minute),
+		WithSchemaFields("user"),
+	)
+	c = l.Config()
+	if c.MinSeverity != logging.Warning || c.SeverityKey != "level" || c.CloseMessage != "logger stopped" ||
+		!c.StdoutJSON || c.DeliveryWatchdog != time.Minute {
+		t.Errorf("config = %+v", c)
+	}
+	if want := map[string]string{"team": "billing"}; !reflect.DeepEqual(c.CommonLabels, want) {
+		t.Errorf("CommonLabels = %v, want %v", c.CommonLabels, want)
+	}
+
+	c.CommonLabels["team"] = "changed"
+	c.SchemaFields[0] = "changed"
+	c = l.Config()
+	if c.CommonLabels["team"] != "billing" || c.SchemaFields[0] != "user" {
+		t.Errorf("modifying a returned config changed the logger's: %+v", c)
+	}
+}
@@ -0,0 +1,110 @@
+package cloudlogging
+
+import (
+	"context"
+	"sync"
+
+	"cloud.google.com/go/logging"
+	"google.golang.org/api/option"
+)
+
+// connection is the client a logger shares with the loggers created from it
+// by Named. The delivery watchdog replaces the client when delivery stalls;
+// every logger then recreates its client loggers from the new one.
+type connection struct {
+	parent  string
+	options []option.ClientOption
+	onError func(error)
+
+	mu      sync.RWMutex
+	client  *logging.Client
+	gen     int
+	retired []*logging.Client
+}
+
+func newConnection(ctx context.Context, parent string, options []option.ClientOption, onError func(error)) (*connection, error) {
+	c := &connection{parent: parent, options: options, onError: onError}
+	client, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+	return c, nil
+}
+
+func (c *connection) dial(ctx context.Context) (*logging.Client, error) {
+	client, err := logging.NewClient(ctx, c.parent, c.options...)
+	if err != nil {
+		return nil, err
+	}
+	client.OnError = c.onError
+	return client, nil
+}
+
+// current returns the client and how many times it was replaced.
+func (c *connection) current() (*logging.Client, int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client, c.gen
+}
+
+// reconnect replaces the client with a new one. The replaced client keeps the
+// entries it holds, and is closed with the logger.
+func (c *connection) reconnect(ctx context.Context) error {
+	client, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.retired = append(c.retired, c.client)
+	c.client = client
+	c.gen++
+	c.mu.Unlock()
+	return nil
+}
+
+// close closes the client. Replaced clients are closed without waiting, as
+// they were replaced for not delivering.
+func (c *connection) close() error {
+	c.mu.Lock()
+	client, retired := c.client, c.retired
+	c.retired = nil
+	c.mu.Unlock()
+
+	for _, old := range retired {
+		go old.Close()
+	}
+	return client.Close()
+}
+
+// discard closes every client without waiting for delivery.
+func (c *connection) discard() {
+	c.mu.Lock()
+	clients := append(c.retired, c.client)
+	c.retired = nil
+	c.mu.Unlock()
+
+	for _, client := range clients {
+		go client.Close()
+	}
+}
+
+// clientLogger returns the client logger of l's own log, recreating it once
+// the client has been replaced.
+func (l *Logger) clientLogger() *logging.Logger {
+	client, gen := l.conn.current()
+	l.loggerMu.RLock()
+	logger, stale := l.logger, l.loggerGen != gen
+	l.loggerMu.RUnlock()
+	if !stale {
+		return logger
+	}
+
+	l.loggerMu.Lock()
+	defer l.loggerMu.Unlock()
+	if l.loggerGen != gen {
+		l.logger = client.Logger(l.name, l.loggerOptions()...)
+		l.loggerGen = gen
+	}
+	return l.logger
+}
@@ -0,0 +1,126 @@
+package cloudlogging
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/logging"
+)
+
+type traceparentKey struct{}
+
+// ContextWithTraceparent returns a copy of ctx carrying a W3C traceparent
+// header value. Context-aware logging methods use it to set the entry's trace,
+// span and sampling decision.
+func ContextWithTraceparent(ctx context.Context, traceparent string) context.Context {
+	return context.WithValue(ctx, traceparentKey{}, traceparent)
+}
+
+// WithRequestIDLabel sets the entry label labelKey to the request ID returned
+// by extractor for the context passed to the context-aware logging methods.
+// The label is left out when extractor returns "".
+func WithRequestIDLabel(labelKey string, extractor func(context.Context) string) Option {
+	return func(l *Logger) {
+		l.requestIDLabel = labelKey
+		l.requestID = extractor
+	}
+}
+
+// WithDeadlineField makes the context-aware logging methods add the time
+// left until the context's deadline as a "deadline_remaining_ms" detail,
+// negative once it has passed, to help diagnose timeouts. Contexts without a
+// deadline add nothing.
+func WithDeadlineField(enabled bool) Option {
+	return func(l *Logger) {
+		l.deadlineField = enabled
+	}
+}
+
+// ParseTraceparent parses a W3C traceparent header value such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01". ok is false
+// when the value is malformed.
+func ParseTraceparent(traceparent string) (traceID, spanID string, sampled, ok bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || (parts[0] == "00" && len(parts) != 4) {
+		return "", "", false, false
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isHex(version, 2) || version == "ff" ||
+		!isHex(traceID, 32) || traceID == strings.Repeat("0", 32) ||
+		!isHex(spanID, 16) || spanID == strings.Repeat("0", 16) ||
+		!isHex(flags, 2) {
+		return "", "", false, false
+	}
+	f, _ := strconv.ParseUint(flags, 16, 8)
+	return traceID, spanID, f&1 == 1, true
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// applyContext sets the entry fields derived from a per-call context.
+func (l *Logger) applyContext(ctx context.Context, entry *logging.Entry) {
+	if l.requestID != nil {
+		if id := l.requestID(ctx); id != "" {
+			if entry.Labels == nil {
+				entry.Labels = make(map[string]string)
+			}
+			entry.Labels[l.requestIDLabel] = id
+		}
+	}
+	if id := CorrelationID(ctx); id != "" {
+		if entry.Labels == nil {
+			entry.Labels = make(map[string]string)
+		}
+		entry.Labels[correlationLabel] = id
+	}
+	if tp, ok := ctx.Value(traceparentKey{}).(string); ok {
+		if traceID, spanID, sampled, ok := ParseTraceparent(tp); ok {
+			entry.Trace = l.traceName(traceID)
+			entry.SpanID = spanID
+			entry.TraceSampled = sampled
+		}
+	}
+}
+
+// traceName returns the full resource name Cloud Logging expects for a trace.
+func (l *Logger) traceName(traceID string) string {
+	if l.projectID == "" {
+		return traceID
+	}
+	return fmt.Sprintf("projects/%s/traces/%s", l.projectID, traceID)
+}
+
+// logContext logs data like log does, with the entry fields derived from ctx.
+func (l *Logger) logContext(ctx context.Context, severity logging.Severity, data map[string]string) {
+	l.write(l.contextEntry(ctx, severity, data), data)
+}
+
+// contextEntry enriches data and returns the entry carrying it, with the entry
+// fields derived from ctx.
+func (l *Logger) contextEntry(ctx context.Context, severity logging.Severity, data map[string]string) logging.Entry {
+	severity = l.severityFromDetail(severity, data)
+	if l.deadlineField {
+		if deadline, ok := ctx.Deadline(); ok {
+			data["deadline_remaining_ms"] = milliseconds(deadline.Sub(l.now()))
+		}
+	}
+	l.enrich(severity, data)
+	entry := logging.Entry{
+		Payload:  data,
+		Severity: severity,
+	}
+	l.applyContext(ctx, &entry)
+	return entry
+}
@@ -0,0 +1,106 @@
+package cloudlogging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	for _, tt := range []struct {
+		name, header string
+		sampled, ok  bool
+	}{
+		{"sampled", "00-" + traceID + "-" + spanID + "-01", true, true},
+		{"not sampled", "00-" + traceID + "-" + spanID + "-00", false, true},
+		{"surrounding space", " 00-" + traceID + "-" + spanID + "-01 ", true, true},
+		{"future version with extra field", "01-" + traceID + "-" + spanID + "-01-extra", true, true},
+		{"version 00 with extra field", "00-" + traceID + "-" + spanID + "-01-extra", false, false},
+		{"invalid version", "ff-" + traceID + "-" + spanID + "-01", false, false},
+		{"zero trace ID", "00-00000000000000000000000000000000-" + spanID + "-01", false, false},
+		{"zero span ID", "00-" + traceID + "-0000000000000000-01", false, false},
+		{"upper case", "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01", false, false},
+		{"short trace ID", "00-4bf92f35-" + spanID + "-01", false, false},
+		{"missing flags", "00-" + traceID + "-" + spanID, false, false},
+		{"empty", "", false, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTrace, gotSpan, sampled, ok := ParseTraceparent(tt.header)
+			if ok != tt.ok || sampled != tt.sampled {
+				t.Fatalf("ParseTraceparent(%q) sampled, ok = %v, %v, want %v, %v", tt.header, sampled, ok, tt.sampled, tt.ok)
+			}
+			if ok && (gotTrace != traceID || gotSpan != spanID) {
+				t.Errorf("ParseTraceparent(%q) = %q, %q, want %q, %q", tt.header, gotTrace, gotSpan, traceID, spanID)
+			}
+		})
+	}
+}
+
+func TestRequestIDLabel(t *testing.T) {
+	type requestIDKey struct{}
+	extract := func(ctx context.Context) string {
+		id, _ := ctx.Value(requestIDKey{}).(string)
+		return id
+	}
+	l := newTestLogger(io.Discard, WithRequestIDLabel("request_id", extract))
+
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"set", context.WithValue(context.Background(), requestIDKey{}, "req-42"), "req-42"},
+		{"empty", context.WithValue(context.Background(), requestIDKey{}, ""), ""},
+		{"absent", context.Background(), ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := errors.New("timeout")
+			entries := l.Capture(func() { l.LogOnError(tt.ctx, &err, "handler failed") })
+
+			got, ok := entries[0].Labels["request_id"]
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("request_id label = %q (present %t), want %q", got, ok, tt.want)
+			}
+			if _, ok := entries[0].Details["request_id"]; ok {
+				t.Error("request ID set in the payload, want only the label")
+			}
+		})
+	}
+}
+
+func TestDeadlineField(t *testing.T) {
+	clock := newFakeClock()
+	withDeadline := func(d time.Duration) context.Context {
+		ctx, cancel := context.WithDeadline(context.Background(), clock.Now().Add(d))
+		t.Cleanup(cancel)
+		return ctx
+	}
+	for _, tt := range []struct {
+		name    string
+		enabled bool
+		ctx     context.Context
+		want    string
+	}{
+		{"remaining", true, withDeadline(1500 * time.Millisecond), "1500"},
+		{"passed", true, withDeadline(-250 * time.Millisecond), "-250"},
+		{"no deadline", true, context.Background(), ""},
+		{"disabled", false, withDeadline(time.Second), ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLogger(io.Discard, WithClock(clock.Now), WithDeadlineField(tt.enabled))
+			err := errors.New("timeout")
+			entries := l.Capture(func() { l.LogOnError(tt.ctx, &err, "query failed") })
+
+			got, ok := entries[0].Details["deadline_remaining_ms"]
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("deadline_remaining_ms = %q (present %t), want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
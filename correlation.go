@@ -0,0 +1,127 @@
+package cloudlogging
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultCorrelationHeader is the header and metadata key used by the
+// correlation middleware when none is given.
+const DefaultCorrelationHeader = "X-Correlation-ID"
+
+// correlationLabel is the entry label holding the correlation ID.
+const correlationLabel = "correlation_id"
+
+// maxCorrelationIDLength bounds the incoming correlation IDs that are kept.
+const maxCorrelationIDLength = 128
+
+type correlationKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying id. Context-aware
+// logging methods set it as the "correlation_id" label.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, or "".
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// CorrelationMiddleware reads the correlation ID of each request from header,
+// generating a UUID when it is missing, stores it in the request context and
+// echoes it in the response header. header defaults to
+// DefaultCorrelationHeader. Incoming IDs longer than 128 bytes or with
+// characters other than ASCII letters, digits, '-', '_', '.' and ':' are
+// replaced by a generated one, as they end up in labels and response headers.
+func CorrelationMiddleware(header string, next http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultCorrelationHeader
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(header)
+		if !validCorrelationID(id) {
+			id = newUUID()
+		}
+		w.Header().Set(header, id)
+		next.ServeHTTP(w, r.WithContext(ContextWithCorrelationID(r.Context(), id)))
+	})
+}
+
+// UnaryCorrelationInterceptor is CorrelationMiddleware for unary gRPC calls,
+// reading and setting the metadata key instead of a header.
+func UnaryCorrelationInterceptor(key string) grpc.UnaryServerInterceptor {
+	key = correlationMetadataKey(key)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id := incomingCorrelationID(ctx, key)
+		grpc.SetHeader(ctx, metadata.Pairs(key, id))
+		return handler(ContextWithCorrelationID(ctx, id), req)
+	}
+}
+
+// StreamCorrelationInterceptor is CorrelationMiddleware for streaming gRPC
+// calls, reading and setting the metadata key instead of a header.
+func StreamCorrelationInterceptor(key string) grpc.StreamServerInterceptor {
+	key = correlationMetadataKey(key)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := incomingCorrelationID(ss.Context(), key)
+		ss.SetHeader(metadata.Pairs(key, id))
+		return handler(srv, correlatedStream{ss, ContextWithCorrelationID(ss.Context(), id)})
+	}
+}
+
+type correlatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s correlatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// correlationMetadataKey returns key, defaulted and lowercased as gRPC
+// metadata keys are.
+func correlationMetadataKey(key string) string {
+	if key == "" {
+		key = DefaultCorrelationHeader
+	}
+	return strings.ToLower(key)
+}
+
+func incomingCorrelationID(ctx context.Context, key string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(key); len(values) > 0 && validCorrelationID(values[0]) {
+			return values[0]
+		}
+	}
+	return newUUID()
+}
+
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLength {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == ':') {
+			return false
+		}
+	}
+	return true
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
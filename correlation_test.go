@@ -0,0 +1,140 @@
+package cloudlogging
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// correlationCases are the incoming IDs tested, with whether each is kept.
+var correlationCases = []struct {
+	name, id string
+	kept     bool
+}{
+	{"present", "req-42.a_b:c", true},
+	{"absent", "", false},
+	{"too long", strings.Repeat("a", maxCorrelationIDLength+1), false},
+	{"longest", strings.Repeat("a", maxCorrelationIDLength), true},
+	{"space", "req 42", false},
+	{"control character", "req\x0042", false},
+	{"non-ASCII", "réq", false},
+}
+
+func checkCorrelationID(t *testing.T, incoming, got, echoed string, kept bool) {
+	t.Helper()
+	if kept && got != incoming {
+		t.Errorf("context ID = %q, want the incoming %q", got, incoming)
+	}
+	if !kept && !uuidPattern.MatchString(got) {
+		t.Errorf("context ID = %q, want a generated UUID", got)
+	}
+	if echoed != got {
+		t.Errorf("echoed ID = %q, want %q", echoed, got)
+	}
+}
+
+func TestCorrelationMiddleware(t *testing.T) {
+	for _, tt := range correlationCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := CorrelationMiddleware("X-Request-Trace", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = CorrelationID(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.id != "" {
+				req.Header["X-Request-Trace"] = []string{tt.id}
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			checkCorrelationID(t, tt.id, got, rec.Header().Get("X-Request-Trace"), tt.kept)
+		})
+	}
+}
+
+// fakeTransportStream records the header set by a unary interceptor.
+type fakeTransportStream struct {
+	header metadata.MD
+}
+
+func (s *fakeTransportStream) Method() string { return "/test.Service/Method" }
+
+func (s *fakeTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeTransportStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+func (s *fakeTransportStream) SetTrailer(metadata.MD) error { return nil }
+
+func TestUnaryCorrelationInterceptor(t *testing.T) {
+	intercept := UnaryCorrelationInterceptor("")
+	for _, tt := range correlationCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &fakeTransportStream{}
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+			if tt.id != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-correlation-id", tt.id))
+			}
+			var got string
+			intercept(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				got = CorrelationID(ctx)
+				return nil, nil
+			})
+			checkCorrelationID(t, tt.id, got, strings.Join(stream.header.Get("x-correlation-id"), ","), tt.kept)
+		})
+	}
+}
+
+// fakeServerStream is a grpc.ServerStream recording the header set on it.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestStreamCorrelationInterceptor(t *testing.T) {
+	intercept := StreamCorrelationInterceptor("X-Request-Trace")
+	for _, tt := range correlationCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.id != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-request-trace", tt.id))
+			}
+			stream := &fakeServerStream{ctx: ctx}
+			var got string
+			intercept(nil, stream, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+				got = CorrelationID(ss.Context())
+				return nil
+			})
+			checkCorrelationID(t, tt.id, got, strings.Join(stream.header.Get("x-request-trace"), ","), tt.kept)
+		})
+	}
+}
+
+func TestCorrelationIDLabel(t *testing.T) {
+	l := newTestLogger(io.Discard)
+	entries := l.Capture(func() {
+		l.logContext(ContextWithCorrelationID(context.Background(), "req-42"), logging.Info, payload("handled"))
+	})
+	if len(entries) != 1 || entries[0].Labels[correlationLabel] != "req-42" {
+		t.Errorf("captured %+v, want the correlation_id label", entries)
+	}
+}
@@ -0,0 +1,59 @@
+package cloudlogging
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+type dampener struct {
+	mu    sync.Mutex
+	start time.Time
+	count int
+}
+
+// WithErrorDampening downgrades Error entries to downgradeTo, marked with
+// dampened=true, once more than threshold of them are logged within window,
+// to keep an outage from flooding Error Reporting. A single summary Error is
+// logged when dampening starts in a window.
+func WithErrorDampening(threshold int, window time.Duration, downgradeTo logging.Severity) Option {
+	return func(l *Logger) {
+		l.dampenThreshold = threshold
+		l.dampenWindow = window
+		l.dampenTo = downgradeTo
+	}
+}
+
+func (l *Logger) dampen(entry *logging.Entry, backupData interface{}) {
+	if l.dampenThreshold <= 0 || entry.Severity != logging.Error {
+		return
+	}
+
+	now := l.now()
+	d := &l.dampener
+	d.mu.Lock()
+	if now.Sub(d.start) >= l.dampenWindow {
+		d.start = now
+		d.count = 0
+	}
+	d.count++
+	count := d.count
+	d.mu.Unlock()
+
+	if count <= l.dampenThreshold {
+		return
+	}
+	if count == l.dampenThreshold+1 {
+		data := payload("error volume above threshold, dampening errors",
+			"threshold", strconv.Itoa(l.dampenThreshold),
+			"window", l.dampenWindow.String(),
+			"downgraded_to", l.dampenTo.String())
+		l.deliver(logging.Entry{Payload: data, Severity: logging.Error}, data)
+	}
+	entry.Severity = l.dampenTo
+	if data, ok := backupData.(map[string]string); ok {
+		data["dampened"] = "true"
+	}
+}
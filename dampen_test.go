@@ -0,0 +1,48 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestErrorDampening(t *testing.T) {
+	clock := newFakeClock()
+	l := newTestLogger(io.Discard, WithClock(clock.Now), WithErrorDampening(2, time.Minute, logging.Warning))
+
+	type logged struct {
+		severity logging.Severity
+		message  string
+		dampened string
+	}
+	var got []logged
+	record := func(fn func()) {
+		for _, e := range l.Capture(fn) {
+			got = append(got, logged{e.Severity, e.Message, e.Details["dampened"]})
+		}
+	}
+	record(func() {
+		for i := 0; i < 4; i++ {
+			l.Error("upstream down")
+		}
+		l.Info("still serving")
+	})
+	clock.Advance(time.Minute)
+	record(func() { l.Error("upstream down") })
+
+	want := []logged{
+		{logging.Error, "upstream down", ""},
+		{logging.Error, "upstream down", ""},
+		{logging.Error, "error volume above threshold, dampening errors", ""},
+		{logging.Warning, "upstream down", "true"},
+		{logging.Warning, "upstream down", "true"},
+		{logging.Info, "still serving", ""},
+		{logging.Error, "upstream down", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("logged %v\nwant %v", got, want)
+	}
+}
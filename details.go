@@ -0,0 +1,19 @@
+package cloudlogging
+
+import (
+	"strconv"
+	"time"
+)
+
+// DurationDetail returns d as key/value details: key holds the duration in
+// milliseconds and key+"_unit" holds "ms", so latency fields are recorded the
+// same way everywhere, e.g.
+//
+//	l.Info("request served", DurationDetail("latency", elapsed)...)
+func DurationDetail(key string, d time.Duration) []string {
+	return []string{key, milliseconds(d), key + "_unit", "ms"}
+}
+
+func milliseconds(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64)
+}
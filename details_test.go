@@ -0,0 +1,34 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDurationDetail(t *testing.T) {
+	for _, tt := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0"},
+		{1200 * time.Millisecond, "1200"},
+		{1500 * time.Microsecond, "1.5"},
+		{2 * time.Minute, "120000"},
+	} {
+		got := DurationDetail("latency", tt.d)
+		want := []string{"latency", tt.want, "latency_unit", "ms"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("DurationDetail(%v) = %q, want %q", tt.d, got, want)
+		}
+	}
+
+	l := newTestLogger(io.Discard)
+	entries := l.Capture(func() {
+		l.Info("request served", DurationDetail("latency", 1200*time.Millisecond)...)
+	})
+	if want := map[string]string{"latency": "1200", "latency_unit": "ms"}; !reflect.DeepEqual(entries[0].Details, want) {
+		t.Errorf("details = %v, want %v", entries[0].Details, want)
+	}
+}
@@ -0,0 +1,88 @@
+package cloudlogging
+
+import (
+	"sync"
+
+	"cloud.google.com/go/logging"
+)
+
+const defaultDynamicLogNames = 100
+
+// dynamicLogLabel is the entry label holding the log name of entries sent to
+// the logger's own log because WithDynamicLogNameLimit was reached.
+const dynamicLogLabel = "log_name"
+
+// dynamicLogs holds the client loggers created for EntryBuilder.LogName. The
+// client keeps every logger it creates, with a goroutine and a buffer, until it
+// is closed, so they are never evicted; new names are refused at the limit.
+type dynamicLogs struct {
+	mu      sync.Mutex
+	loggers map[string]*logging.Logger
+	gen     int // the connection generation loggers were created from
+}
+
+// WithDynamicLogNameLimit sets how many distinct names EntryBuilder.LogName
+// can route to. Once the limit is reached, entries for new names go to the
+// logger's own log with the name as the "log_name" label. The default is 100.
+func WithDynamicLogNameLimit(n int) Option {
+	return func(l *Logger) {
+		l.dynamicLogLimit = n
+	}
+}
+
+// LogName sends the entry to the log name instead of the logger's own log,
+// e.g. one log per customer. Each distinct name costs a client logger, with a
+// goroutine and a buffer, for the life of the client, so keep the number of
+// names small; see WithDynamicLogNameLimit.
+func (b *EntryBuilder) LogName(name string) *EntryBuilder {
+	b.logName = name
+	return b
+}
+
+// dynamicLogger returns the client logger for name, creating it on first use,
+// or nil when name is new and the limit is reached.
+func (l *Logger) dynamicLogger(name string) *logging.Logger {
+	client, gen := l.conn.current()
+	d := &l.dynamic
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.gen != gen {
+		d.loggers, d.gen = nil, gen
+	}
+	if logger, ok := d.loggers[name]; ok {
+		return logger
+	}
+	limit := l.dynamicLogLimit
+	if limit <= 0 {
+		limit = defaultDynamicLogNames
+	}
+	if len(d.loggers) >= limit {
+		return nil
+	}
+	if d.loggers == nil {
+		d.loggers = make(map[string]*logging.Logger)
+	}
+	logger := client.Logger(name, l.loggerOptions()...)
+	d.loggers[name] = logger
+	return logger
+}
+
+// flushDynamicLoggers flushes every logger created by dynamicLogger.
+func (l *Logger) flushDynamicLoggers() error {
+	d := &l.dynamic
+	d.mu.Lock()
+	loggers := make([]*logging.Logger, 0, len(d.loggers))
+	for _, logger := range d.loggers {
+		loggers = append(loggers, logger)
+	}
+	d.mu.Unlock()
+
+	var firstErr error
+	for _, logger := range loggers {
+		if err := l.flush(logger); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
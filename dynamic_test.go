@@ -0,0 +1,39 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestDynamicLogNames(t *testing.T) {
+	srv := &fakeServer{}
+	l := newServedLogger(t, srv, io.Discard, WithDynamicLogNameLimit(2))
+	for _, name := range []string{"customer-a", "customer-b", "customer-a", "customer-c"} {
+		l.Entry().Severity(logging.Info).Message("order for " + name).LogName(name).Send()
+	}
+	if got := len(l.dynamic.loggers); got != 2 {
+		t.Errorf("created %d dynamic loggers, want 2", got)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, entry := range srv.received() {
+		got[entry.GetJsonPayload().GetFields()["msg"].GetStringValue()] = entry.LogName + " " + entry.Labels[dynamicLogLabel]
+	}
+	want := map[string]string{
+		"order for customer-a": "projects/test-project/logs/customer-a ",
+		"order for customer-b": "projects/test-project/logs/customer-b ",
+		"order for customer-c": "projects/test-project/logs/test customer-c",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("received %v, want %v", got, want)
+	}
+	if got := srv.total(); got != 4 {
+		t.Errorf("server received %d entries, want 4", got)
+	}
+}
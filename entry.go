@@ -0,0 +1,40 @@
+package cloudlogging
+
+import (
+	"context"
+
+	"cloud.google.com/go/logging"
+)
+
+// LogEntry sends e as built by the caller, for entry fields the other methods
+// do not expose. Only the minimum severity and the severity hooks apply; the
+// latter only to a map[string]string payload. The trace and labels derived
+// from ctx are added where e does not set them. The backup output shows e's
+// payload.
+func (l *Logger) LogEntry(ctx context.Context, e logging.Entry) {
+	e.Severity = l.normalizeSeverity(e.Severity)
+	if !l.enabled(e.Severity) {
+		return
+	}
+	if data, ok := e.Payload.(map[string]string); ok {
+		for _, hook := range l.severityHooks {
+			if l.atLeast(e.Severity, hook.minSeverity) {
+				hook.fn(data)
+			}
+		}
+	}
+
+	var fromCtx logging.Entry
+	l.applyContext(ctx, &fromCtx)
+	if e.Trace == "" {
+		e.Trace, e.SpanID, e.TraceSampled = fromCtx.Trace, fromCtx.SpanID, fromCtx.TraceSampled
+	}
+	if len(fromCtx.Labels) > 0 {
+		labels := copyMap(fromCtx.Labels)
+		for k, v := range e.Labels {
+			labels[k] = v
+		}
+		e.Labels = labels
+	}
+	l.deliver(e, e.Payload)
+}
@@ -0,0 +1,28 @@
+package cloudlogging
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestLogEntryKeepsCallerLabels(t *testing.T) {
+	l := newTestLogger(io.Discard, WithRequestIDLabel("request_id", func(context.Context) string { return "r1" }))
+	labels := map[string]string{"team": "billing"}
+
+	entries := l.Capture(func() {
+		l.LogEntry(context.Background(), logging.Entry{Severity: logging.Info, Payload: "raw", Labels: labels})
+	})
+
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if got := entries[0].Labels; got["team"] != "billing" || got["request_id"] != "r1" {
+		t.Errorf("labels = %v, want team and request_id", got)
+	}
+	if len(labels) != 1 {
+		t.Errorf("caller's labels modified: %v", labels)
+	}
+}
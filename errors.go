@@ -0,0 +1,36 @@
+package cloudlogging
+
+import (
+	"context"
+
+	"cloud.google.com/go/logging"
+)
+
+// ErrorReturn logs msg at Error with err's message as the "error" detail and
+// returns err unchanged, so that logging and returning is one statement:
+//
+//	return l.ErrorReturn(err, "db write failed")
+//
+// A nil err is still logged, without the "error" detail, and nil is returned.
+func (l *Logger) ErrorReturn(err error, msg string, details ...string) error {
+	data := payload(msg, details...)
+	if err != nil {
+		data["error"] = err.Error()
+	}
+	l.logData(logging.Error, data)
+	return err
+}
+
+// LogOnError logs msg at Error with the "error" detail if *errp is non-nil. It
+// is meant to be deferred with a named error result, so that failures are
+// logged at the function boundary:
+//
+//	defer l.LogOnError(ctx, &err, "handler failed")
+func (l *Logger) LogOnError(ctx context.Context, errp *error, msg string, details ...string) {
+	if errp == nil || *errp == nil {
+		return
+	}
+	data := payload(msg, details...)
+	data["error"] = (*errp).Error()
+	l.logContext(ctx, logging.Error, data)
+}
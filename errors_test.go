@@ -0,0 +1,58 @@
+package cloudlogging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestErrorReturn(t *testing.T) {
+	l := newTestLogger(io.Discard)
+	errWrite := errors.New("connection reset")
+
+	var returned, returnedNil error
+	entries := l.Capture(func() {
+		returned = l.ErrorReturn(errWrite, "db write failed", "table", "orders")
+		returnedNil = l.ErrorReturn(nil, "db write skipped")
+	})
+
+	if returned != errWrite {
+		t.Errorf("returned %v, want the logged error itself", returned)
+	}
+	if returnedNil != nil {
+		t.Errorf("returned %v for a nil error, want nil", returnedNil)
+	}
+	want := []Entry{
+		{Severity: logging.Error, Message: "db write failed", Details: map[string]string{"table": "orders", "error": "connection reset"}, Labels: map[string]string{}},
+		{Severity: logging.Error, Message: "db write skipped", Details: map[string]string{}, Labels: map[string]string{}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v\nwant %+v", entries, want)
+	}
+}
+
+func TestLogOnError(t *testing.T) {
+	l := newTestLogger(io.Discard)
+	handle := func(fail bool) (err error) {
+		defer l.LogOnError(context.Background(), &err, "handler failed", "route", "/pay")
+		if fail {
+			return errors.New("card declined")
+		}
+		return nil
+	}
+
+	entries := l.Capture(func() {
+		handle(false)
+		handle(true)
+	})
+	want := []Entry{
+		{Severity: logging.Error, Message: "handler failed", Details: map[string]string{"route": "/pay", "error": "card declined"}, Labels: map[string]string{}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v\nwant %+v", entries, want)
+	}
+}
@@ -0,0 +1,52 @@
+package cloudlogging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// CloudEventFields are the CloudEvents context attributes of an entry. ID,
+// Source and Type are required.
+type CloudEventFields struct {
+	ID      string
+	Source  string
+	Type    string
+	Subject string
+	Time    time.Time
+}
+
+// LogEvent logs msg with the CloudEvents attributes of evt under their
+// standard keys, so the logs can be consumed as a lightweight event stream.
+// Attributes override details with the same key, and are not renamed by
+// WithKeyPrefix or WithKeyCase. The event time, if set, is also the entry
+// timestamp.
+func (l *Logger) LogEvent(ctx context.Context, severity logging.Severity, msg string, evt CloudEventFields, details ...string) error {
+	for _, required := range []struct{ key, value string }{
+		{"id", evt.ID},
+		{"source", evt.Source},
+		{"type", evt.Type},
+	} {
+		if required.value == "" {
+			return fmt.Errorf("cloudlogging: cloud event %q is required", required.key)
+		}
+	}
+
+	data := payload(msg, details...)
+	entry := l.contextEntry(ctx, severity, data)
+	data["specversion"] = "1.0"
+	data["id"] = evt.ID
+	data["source"] = evt.Source
+	data["type"] = evt.Type
+	if evt.Subject != "" {
+		data["subject"] = evt.Subject
+	}
+	if !evt.Time.IsZero() {
+		entry.Timestamp = evt.Time
+		data["time"] = evt.Time.Format(time.RFC3339Nano)
+	}
+	l.write(entry, data)
+	return nil
+}
@@ -0,0 +1,94 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestLogEventRequiredFields(t *testing.T) {
+	valid := CloudEventFields{ID: "42", Source: "/billing", Type: "invoice.paid"}
+	for _, tt := range []struct {
+		name    string
+		edit    func(*CloudEventFields)
+		wantErr string
+	}{
+		{"valid", func(*CloudEventFields) {}, ""},
+		{"missing id", func(e *CloudEventFields) { e.ID = "" }, `"id"`},
+		{"missing source", func(e *CloudEventFields) { e.Source = "" }, `"source"`},
+		{"missing type", func(e *CloudEventFields) { e.Type = "" }, `"type"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			evt := valid
+			tt.edit(&evt)
+			l := newTestLogger(io.Discard)
+			var err error
+			entries := l.Capture(func() {
+				err = l.LogEvent(context.Background(), logging.Info, "event", evt)
+			})
+			if tt.wantErr == "" {
+				if err != nil || len(entries) != 1 {
+					t.Fatalf("LogEvent = %v with %d entries, want no error and 1 entry", err, len(entries))
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LogEvent error = %v, want one naming %s", err, tt.wantErr)
+			}
+			if len(entries) != 0 {
+				t.Errorf("LogEvent logged %d entries for an invalid event", len(entries))
+			}
+		})
+	}
+}
+
+func TestLogEventKeepsAttributeKeys(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLogger(io.Discard, WithKeyPrefix("billing."), WithKeyCase(Snake))
+	entries := l.Capture(func() {
+		l.LogEvent(context.Background(), logging.Info, "paid", CloudEventFields{
+			ID: "42", Source: "/billing", Type: "invoice.paid", Time: at,
+		}, "invoiceID", "7")
+	})
+	if len(entries) != 1 {
+		t.Fatalf("captured %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	want := map[string]string{
+		"specversion":        "1.0",
+		"id":                 "42",
+		"source":             "/billing",
+		"type":               "invoice.paid",
+		"time":               at.Format(time.RFC3339Nano),
+		"billing.invoice_id": "7",
+	}
+	for k, v := range want {
+		if e.Details[k] != v {
+			t.Errorf("detail %q = %q, want %q (details %v)", k, e.Details[k], v, e.Details)
+		}
+	}
+	if !e.Timestamp.Equal(at) {
+		t.Errorf("timestamp = %v, want the event time %v", e.Timestamp, at)
+	}
+}
+
+func TestLogEventTimeInLogfmt(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	l := newTestLogger(io.Discard, WithLogfmtSink(&out), WithClock(func() time.Time { return at.Add(time.Hour) }))
+	l.LogEvent(context.Background(), logging.Info, "paid", CloudEventFields{
+		ID: "42", Source: "/billing", Type: "invoice.paid", Time: at,
+	})
+	line := out.String()
+	if n := strings.Count(line, "time="); n != 1 {
+		t.Errorf("line has %d time keys, want 1: %s", n, line)
+	}
+	if !strings.HasPrefix(line, "time="+at.Format(time.RFC3339Nano)+" ") {
+		t.Errorf("line does not start with the event time: %s", line)
+	}
+}
@@ -0,0 +1,84 @@
+package cloudlogging
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// WithFlushOnBufferSize makes the client send buffered entries as soon as n
+// have accumulated, rather than only once its delay threshold of one second
+// has passed, whichever comes first. This bounds memory and latency under
+// bursts.
+func WithFlushOnBufferSize(n int) Option {
+	return func(l *Logger) {
+		l.flushBufferSize = n
+	}
+}
+
+// FlushCtx blocks until every entry logged so far has been sent, or until ctx
+// is done. On return without error everything logged before the call has been
+// delivered. The flush itself keeps running when ctx ends first.
+func (l *Logger) FlushCtx(ctx context.Context) error {
+	if l.conn == nil {
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- l.flushAll()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flushAll flushes every client logger of l. On success, the entries pending
+// when it started are no longer counted as pending.
+func (l *Logger) flushAll() error {
+	pending := atomic.LoadInt64(&l.pending)
+	err := l.flush(l.clientLogger())
+	if subErr := l.flushSubLoggers(); err == nil {
+		err = subErr
+	}
+	if err == nil {
+		atomic.AddInt64(&l.pending, -pending)
+	}
+	return err
+}
+
+// States of Logger.flushes.
+const (
+	flushIdle int32 = iota
+	flushRunning
+	flushAgain
+)
+
+// requestFlush runs flushAll in the background. Requests made while a flush
+// runs are coalesced into one more flush after it, so at most one flush runs
+// at a time however many entries ask for one.
+func (l *Logger) requestFlush() {
+	for {
+		state := atomic.LoadInt32(&l.flushes)
+		if state == flushAgain {
+			return
+		}
+		if !atomic.CompareAndSwapInt32(&l.flushes, state, state+1) {
+			continue
+		}
+		if state == flushIdle {
+			l.goBackground(func(<-chan struct{}) {
+				for {
+					l.flushAll()
+					if atomic.CompareAndSwapInt32(&l.flushes, flushRunning, flushIdle) {
+						return
+					}
+					atomic.StoreInt32(&l.flushes, flushRunning)
+				}
+			})
+		}
+		return
+	}
+}
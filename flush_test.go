@@ -0,0 +1,92 @@
+package cloudlogging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// TestFlushOnSeverity relies on the client holding entries for a second
+// before sending them on its own.
+func TestFlushOnSeverity(t *testing.T) {
+	for _, tt := range []struct {
+		severity logging.Severity
+		flushed  bool
+	}{
+		{logging.Info, false},
+		{logging.Error, true},
+		{logging.Critical, true},
+	} {
+		t.Run(tt.severity.String(), func(t *testing.T) {
+			srv := &fakeServer{}
+			l := newServedLogger(t, srv, io.Discard, WithFlushOnSeverity(logging.Error))
+			defer l.Close()
+
+			l.Logf(tt.severity, "payment failed")
+			deadline := time.Now().Add(400 * time.Millisecond)
+			for srv.total() == 0 && time.Now().Before(deadline) {
+				time.Sleep(5 * time.Millisecond)
+			}
+			if got := srv.total() == 1; got != tt.flushed {
+				t.Errorf("entry delivered before the client's delay: %t, want %t", got, tt.flushed)
+			}
+		})
+	}
+}
+
+func TestFlushCtx(t *testing.T) {
+	srv := &fakeServer{}
+	l := newServedLogger(t, srv, io.Discard)
+	defer l.Close()
+	for i := 0; i < 3; i++ {
+		l.Info("queued")
+	}
+
+	if err := l.FlushCtx(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.total(); got != 3 {
+		t.Errorf("server received %d entries after FlushCtx, want 3", got)
+	}
+	if got := atomic.LoadInt64(&l.pending); got != 0 {
+		t.Errorf("%d entries pending after FlushCtx, want 0", got)
+	}
+}
+
+func TestFlushCtxDeadline(t *testing.T) {
+	srv := &fakeServer{block: make(chan struct{})}
+	l := newServedLogger(t, srv, io.Discard, WithCloseMode(CloseDiscard))
+	defer l.Close()
+	defer close(srv.block)
+	l.Info("held by the server")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := l.FlushCtx(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FlushCtx() = %v, want the context's deadline error", err)
+	}
+}
+
+// TestFlushOnBufferSize relies on the client holding entries for a second
+// before sending them on its own.
+func TestFlushOnBufferSize(t *testing.T) {
+	srv := &fakeServer{}
+	l := newServedLogger(t, srv, io.Discard, WithFlushOnBufferSize(5))
+	defer l.Close()
+
+	for i := 0; i < 7; i++ {
+		l.Info("burst")
+	}
+	deadline := time.Now().Add(400 * time.Millisecond)
+	for srv.total() < 5 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := srv.total(); got != 5 {
+		t.Errorf("server received %d entries before the client's delay, want the 5 reaching the threshold", got)
+	}
+}
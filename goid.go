@@ -0,0 +1,29 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// WithGoroutineID adds the ID of the logging goroutine as a "goid" field.
+// Reading it means capturing a stack header on every entry, so this is meant
+// for chasing concurrency bugs, not for production.
+func WithGoroutineID(enabled bool) Option {
+	return func(l *Logger) {
+		l.goroutineID = enabled
+	}
+}
+
+func goroutineID() string {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	if _, err := strconv.ParseUint(string(b), 10, 64); err != nil {
+		return ""
+	}
+	return string(b)
+}
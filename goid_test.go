@@ -0,0 +1,46 @@
+package cloudlogging
+
+import (
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestGoroutineID(t *testing.T) {
+	l := newTestLogger(io.Discard, WithGoroutineID(true))
+	entries := l.Capture(func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.Info("first")
+				l.Info("second")
+			}()
+		}
+		wg.Wait()
+	})
+
+	perGoroutine := make(map[string]int)
+	for _, e := range entries {
+		goid := e.Details["goid"]
+		if goid == "" {
+			t.Fatalf("%q has no goid", e.Message)
+		}
+		perGoroutine[goid]++
+	}
+	if len(perGoroutine) != 4 {
+		t.Errorf("entries carry %d distinct goids, want 4: %v", len(perGoroutine), perGoroutine)
+	}
+	for goid, n := range perGoroutine {
+		if n != 2 {
+			t.Errorf("goid %s on %d entries, want 2", goid, n)
+		}
+	}
+
+	l = newTestLogger(io.Discard, WithGoroutineID(false))
+	entries = l.Capture(func() { l.Info("disabled") })
+	if goid, ok := entries[0].Details["goid"]; ok {
+		t.Errorf("goid = %q with WithGoroutineID(false), want none", goid)
+	}
+}
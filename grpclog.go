@@ -0,0 +1,99 @@
+package cloudlogging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/logging"
+	"google.golang.org/grpc/grpclog"
+)
+
+type grpcLogger struct {
+	logger    ILogger
+	info      bool
+	verbosity int
+}
+
+// SetAsGRPCLogger makes gRPC log its internal warnings and errors, such as
+// connection errors, through logger instead of to stderr. Warning and Error
+// map to the matching severities and Fatal to Critical, after which the
+// logger is closed, if it can be, and the process exits as gRPC expects.
+// gRPC's Info messages, such as resolver events, are dropped; see
+// SetAsGRPCLoggerVerbose. It must be called before any gRPC activity.
+func SetAsGRPCLogger(logger ILogger) {
+	grpclog.SetLoggerV2(&grpcLogger{logger: logger})
+}
+
+// SetAsGRPCLoggerVerbose is like SetAsGRPCLogger but also forwards gRPC's Info
+// messages up to verbosity. They are chatty and include those of the Cloud
+// Logging client's own connection, so connection trouble produces entries
+// sent through the failing connection; prefer a logger writing elsewhere.
+func SetAsGRPCLoggerVerbose(logger ILogger, verbosity int) {
+	grpclog.SetLoggerV2(&grpcLogger{logger: logger, info: true, verbosity: verbosity})
+}
+
+func (g *grpcLogger) Info(args ...interface{}) {
+	if g.info {
+		g.print(logging.Info, args)
+	}
+}
+
+func (g *grpcLogger) Infoln(args ...interface{}) {
+	if g.info {
+		g.println(logging.Info, args)
+	}
+}
+
+func (g *grpcLogger) Infof(format string, args ...interface{}) {
+	if g.info {
+		g.logger.Logf(logging.Info, format, args...)
+	}
+}
+
+func (g *grpcLogger) Warning(args ...interface{})   { g.print(logging.Warning, args) }
+func (g *grpcLogger) Warningln(args ...interface{}) { g.println(logging.Warning, args) }
+func (g *grpcLogger) Warningf(format string, args ...interface{}) {
+	g.logger.Logf(logging.Warning, format, args...)
+}
+
+func (g *grpcLogger) Error(args ...interface{})   { g.print(logging.Error, args) }
+func (g *grpcLogger) Errorln(args ...interface{}) { g.println(logging.Error, args) }
+func (g *grpcLogger) Errorf(format string, args ...interface{}) {
+	g.logger.Logf(logging.Error, format, args...)
+}
+
+func (g *grpcLogger) Fatal(args ...interface{}) {
+	g.print(logging.Critical, args)
+	g.exit()
+}
+
+func (g *grpcLogger) Fatalln(args ...interface{}) {
+	g.println(logging.Critical, args)
+	g.exit()
+}
+
+func (g *grpcLogger) Fatalf(format string, args ...interface{}) {
+	g.logger.Logf(logging.Critical, format, args...)
+	g.exit()
+}
+
+func (g *grpcLogger) V(l int) bool {
+	return g.info && l <= g.verbosity
+}
+
+func (g *grpcLogger) print(severity logging.Severity, args []interface{}) {
+	g.logger.Logf(severity, "%s", fmt.Sprint(args...))
+}
+
+func (g *grpcLogger) println(severity logging.Severity, args []interface{}) {
+	g.logger.Logf(severity, "%s", strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+// exit closes the logger so that the fatal entry is delivered, then exits.
+func (g *grpcLogger) exit() {
+	if c, ok := g.logger.(interface{ Close() error }); ok {
+		c.Close()
+	}
+	os.Exit(1)
+}
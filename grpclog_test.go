@@ -0,0 +1,73 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestGRPCLogger(t *testing.T) {
+	type logged struct {
+		severity logging.Severity
+		message  string
+	}
+	for _, tt := range []struct {
+		name    string
+		verbose bool
+		log     func(*grpcLogger)
+		want    []logged
+	}{
+		{"info dropped", false, func(g *grpcLogger) {
+			g.Info("resolver ", "update")
+			g.Infoln("resolver", "update")
+			g.Infof("resolver %s", "update")
+		}, nil},
+		{"info verbose", true, func(g *grpcLogger) {
+			g.Info("resolver ", "update")
+			g.Infoln("resolver", "update")
+			g.Infof("resolver %s", "update")
+		}, []logged{{logging.Info, "resolver update"}, {logging.Info, "resolver update"}, {logging.Info, "resolver update"}}},
+		{"warning", false, func(g *grpcLogger) {
+			g.Warning("retrying after ", 3, " attempts")
+			g.Warningln("retrying after", 3, "attempts")
+			g.Warningf("retrying after %d attempts", 3)
+		}, []logged{{logging.Warning, "retrying after 3 attempts"}, {logging.Warning, "retrying after 3 attempts"}, {logging.Warning, "retrying after 3 attempts"}}},
+		{"error", false, func(g *grpcLogger) {
+			g.Error("connection failed: ", io.ErrUnexpectedEOF)
+			g.Errorln("connection failed:", io.ErrUnexpectedEOF)
+			g.Errorf("connection failed: %v", io.ErrUnexpectedEOF)
+		}, []logged{{logging.Error, "connection failed: unexpected EOF"}, {logging.Error, "connection failed: unexpected EOF"}, {logging.Error, "connection failed: unexpected EOF"}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLogger(io.Discard)
+			g := &grpcLogger{logger: l, info: tt.verbose, verbosity: 2}
+			var got []logged
+			for _, e := range l.Capture(func() { tt.log(g) }) {
+				got = append(got, logged{e.Severity, e.Message})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("logged %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGRPCLoggerV(t *testing.T) {
+	for _, tt := range []struct {
+		g    *grpcLogger
+		want []bool
+	}{
+		{&grpcLogger{}, []bool{false, false, false}},
+		{&grpcLogger{info: true, verbosity: 1}, []bool{true, true, false}},
+	} {
+		var got []bool
+		for v := 0; v <= 2; v++ {
+			got = append(got, tt.g.V(v))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("V(0..2) with info %t and verbosity %d = %v, want %v", tt.g.info, tt.g.verbosity, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package cloudlogging
+
+import (
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// WithHeartbeat logs a "heartbeat" entry at Info every interval with the
+// logger's uptime and entry count, so dashboards can tell a quiet service from
+// a dead one. details are added to every heartbeat. The heartbeat stops when
+// the logger is closed.
+func WithHeartbeat(interval time.Duration, details ...string) Option {
+	return func(l *Logger) {
+		l.heartbeatInterval = interval
+		l.heartbeatDetails = details
+	}
+}
+
+func (l *Logger) startHeartbeat() {
+	if l.heartbeatInterval <= 0 {
+		return
+	}
+	l.every(l.heartbeatInterval, l.heartbeat)
+}
+
+func (l *Logger) heartbeat() {
+	data := payload("heartbeat", l.heartbeatDetails...)
+	data["uptime_ms"] = milliseconds(l.now().Sub(l.started))
+	data["entries"] = strconv.FormatInt(l.Stats().Entries, 10)
+	l.logData(logging.Info, data)
+}
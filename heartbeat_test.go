@@ -0,0 +1,46 @@
+package cloudlogging
+
+import (
+	"context"
+	"io"
+	"log"
+	"strconv"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestHeartbeat(t *testing.T) {
+	clock := newFakeClock()
+	l, err := New(context.Background(), "test-project", "test", log.New(io.Discard, "", 0),
+		WithLogfmtSink(io.Discard), WithClock(clock.Now), WithHeartbeat(5*time.Millisecond, "service", "billing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("served")
+	clock.Advance(90 * time.Second)
+
+	entries := l.Capture(func() { time.Sleep(50 * time.Millisecond) })
+	if len(entries) == 0 {
+		t.Fatal("no heartbeat within 50ms at a 5ms interval")
+	}
+	for _, e := range entries {
+		if e.Severity != logging.Info || e.Message != "heartbeat" {
+			t.Errorf("heartbeat = %v %q, want Info %q", e.Severity, e.Message, "heartbeat")
+		}
+		if e.Details["service"] != "billing" || e.Details["uptime_ms"] != "90000" {
+			t.Errorf("heartbeat details = %v, want service billing and 90000ms of uptime", e.Details)
+		}
+		if n, err := strconv.Atoi(e.Details["entries"]); err != nil || n < 1 {
+			t.Errorf("heartbeat entries = %q, want the entries logged so far", e.Details["entries"])
+		}
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if entries := l.Capture(func() { time.Sleep(20 * time.Millisecond) }); len(entries) != 0 {
+		t.Errorf("%d heartbeats after Close, want none", len(entries))
+	}
+}
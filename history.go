@@ -0,0 +1,31 @@
+package cloudlogging
+
+import (
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// DeliveryError is a delivery failure recorded by WithErrorHistory.
+type DeliveryError struct {
+	Err  error
+	Time time.Time
+	// Severity is the severity of the entry that failed. Errors reported
+	// asynchronously by the client are not tied to an entry and have
+	// logging.Default.
+	Severity logging.Severity
+}
+
+// WithErrorHistory keeps the last k delivery errors for ErrorHistory.
+func WithErrorHistory(k int) Option {
+	return func(l *Logger) {
+		l.errorHistorySize = k
+	}
+}
+
+// ErrorHistory returns the recorded delivery errors, oldest first.
+func (l *Logger) ErrorHistory() []DeliveryError {
+	l.errMu.Lock()
+	defer l.errMu.Unlock()
+	return append([]DeliveryError(nil), l.errorHistory...)
+}
@@ -0,0 +1,48 @@
+package cloudlogging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHistory(t *testing.T) {
+	clock := newFakeClock()
+	l := newTestLogger(io.Discard, WithClock(clock.Now), WithErrorHistory(2))
+
+	errs := []error{errors.New("first"), errors.New("second"), errors.New("third")}
+	var want []DeliveryError
+	for i, err := range errs {
+		severity := []logging.Severity{logging.Info, logging.Error, logging.Warning}[i]
+		l.recordError(err, severity)
+		want = append(want, DeliveryError{Err: err, Time: clock.Now(), Severity: severity})
+		clock.Advance(time.Second)
+	}
+
+	if got := l.ErrorHistory(); !reflect.DeepEqual(got, want[1:]) {
+		t.Errorf("ErrorHistory() = %v, want the last two, oldest first: %v", got, want[1:])
+	}
+	l.ClearErrors()
+	if got := l.ErrorHistory(); len(got) != 0 {
+		t.Errorf("ErrorHistory() after ClearErrors = %v, want empty", got)
+	}
+}
+
+func TestErrorHistoryFromServer(t *testing.T) {
+	srv := &fakeServer{fail: status.Error(codes.PermissionDenied, "denied")}
+	l := newServedLogger(t, srv, io.Discard, WithErrorHistory(4))
+	defer l.Close()
+
+	l.CheckPermissions(context.Background())
+	history := l.ErrorHistory()
+	if len(history) != 1 || history[0].Severity != logging.Debug || status.Code(history[0].Err) != codes.PermissionDenied {
+		t.Errorf("ErrorHistory() = %v, want the denied Debug check entry", history)
+	}
+}
@@ -0,0 +1,55 @@
+package cloudlogging
+
+import (
+	"net/http"
+	"strings"
+
+	"cloud.google.com/go/logging"
+)
+
+// HeaderDetails returns the allowlisted headers of h as key/value details,
+// ready to be passed to the logging methods. Headers missing from h are
+// omitted, and headers not in the allowlist, such as Authorization, are
+// never captured.
+func HeaderDetails(h http.Header, allowlist ...string) []string {
+	details := make([]string, 0, 2*len(allowlist))
+	for _, name := range allowlist {
+		values := h.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		details = append(details, name, strings.Join(values, ", "))
+	}
+	return details
+}
+
+// SeverityForStatus maps an HTTP status code to a severity: 5xx to Error, 4xx
+// to Warning and 1xx to 3xx to Info. Zero and unknown codes map to Default.
+func SeverityForStatus(code int) logging.Severity {
+	switch {
+	case code >= 500 && code < 600:
+		return logging.Error
+	case code >= 400 && code < 500:
+		return logging.Warning
+	case code >= 100 && code < 400:
+		return logging.Info
+	default:
+		return logging.Default
+	}
+}
+
+// WithStatusSeverity overrides the mapping used by Logger.SeverityForStatus.
+func WithStatusSeverity(mapping func(code int) logging.Severity) Option {
+	return func(l *Logger) {
+		l.statusSeverity = mapping
+	}
+}
+
+// SeverityForStatus maps an HTTP status code to a severity using the mapping
+// set by WithStatusSeverity, or the package-level SeverityForStatus.
+func (l *Logger) SeverityForStatus(code int) logging.Severity {
+	if l.statusSeverity != nil {
+		return l.statusSeverity(code)
+	}
+	return SeverityForStatus(code)
+}
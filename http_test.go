@@ -0,0 +1,52 @@
+package cloudlogging
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestSeverityForStatus(t *testing.T) {
+	for _, tt := range []struct {
+		code int
+		want logging.Severity
+	}{
+		{0, logging.Default},
+		{99, logging.Default},
+		{100, logging.Info},
+		{200, logging.Info},
+		{304, logging.Info},
+		{399, logging.Info},
+		{400, logging.Warning},
+		{404, logging.Warning},
+		{499, logging.Warning},
+		{500, logging.Error},
+		{503, logging.Error},
+		{599, logging.Error},
+		{600, logging.Default},
+	} {
+		t.Run(strconv.Itoa(tt.code), func(t *testing.T) {
+			if got := SeverityForStatus(tt.code); got != tt.want {
+				t.Errorf("SeverityForStatus(%d) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeaderDetails(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Request-Id", "req-42")
+	h.Add("Retry-After", "120")
+	h.Add("Vary", "Accept")
+	h.Add("Vary", "Origin")
+	h.Set("Authorization", "Bearer secret")
+
+	got := HeaderDetails(h, "X-Request-Id", "retry-after", "Vary", "X-Missing")
+	want := []string{"X-Request-Id", "req-42", "retry-after", "120", "Vary", "Accept, Origin"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HeaderDetails() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,40 @@
+// Package details converts the loosely typed values of the logging libraries
+// adapted by the subpackages into the string details of cloudlogging.
+package details
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// String renders v as a detail value: strings as they are, errors by their
+// message, fmt.Stringers by their String method and other values as JSON,
+// or with fmt when they cannot be marshaled.
+func String(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// AppendPairs appends keysAndValues to dst as key/value details, completing
+// an odd count with "MISSING" as cloudlogging does.
+func AppendPairs(dst []string, keysAndValues []interface{}) []string {
+	for i := 0; i < len(keysAndValues); i += 2 {
+		value := "MISSING"
+		if i+1 < len(keysAndValues) {
+			value = String(keysAndValues[i+1])
+		}
+		dst = append(dst, fmt.Sprint(keysAndValues[i]), value)
+	}
+	return dst
+}
@@ -0,0 +1,112 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"cloud.google.com/go/logging"
+)
+
+// maxJSONLineLength bounds the incomplete line JSONLineWriter buffers, at the
+// Cloud Logging limit on the size of an entry.
+const maxJSONLineLength = 256 * 1024
+
+type jsonLineWriter struct {
+	mu       sync.Mutex
+	logger   *Logger
+	partial  []byte
+	skipping bool // dropping the rest of an overlong line
+}
+
+// WithJSONLineKeys sets the keys JSONLineWriter reads the severity and the
+// message from. The defaults are "severity" and "message", as written by the
+// Cloud Logging agent conventions.
+func WithJSONLineKeys(severityKey, messageKey string) Option {
+	return func(l *Logger) {
+		l.jsonLineSeverityKey = severityKey
+		l.jsonLineMessageKey = messageKey
+	}
+}
+
+// JSONLineWriter returns an io.Writer that parses newline-delimited JSON
+// objects, such as a subprocess's structured output, and logs each as an
+// entry. The severity and message are read from the keys set with
+// WithJSONLineKeys and the other fields become details; non-string values
+// keep their JSON text. Lines that are not JSON objects are logged at Warning
+// as they are. A line split across writes is logged once it is complete; one
+// growing past 256 KiB without a newline is dropped, and counted in
+// Stats.OverlongJSONLinesDropped.
+func (l *Logger) JSONLineWriter() io.Writer {
+	return &jsonLineWriter{logger: l}
+}
+
+func (w *jsonLineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSpace(w.partial[:i])
+		w.partial = w.partial[i+1:]
+		if w.skipping {
+			w.skipping = false
+			continue
+		}
+		if len(line) > 0 {
+			w.logger.logJSONLine(line)
+		}
+	}
+	if len(w.partial) > maxJSONLineLength {
+		if !w.skipping {
+			w.skipping = true
+			w.logger.stats.mu.Lock()
+			w.logger.stats.OverlongJSONLinesDropped++
+			w.logger.stats.mu.Unlock()
+		}
+		w.partial = nil
+	}
+	if len(w.partial) == 0 {
+		w.partial = nil
+	}
+	return len(p), nil
+}
+
+func (l *Logger) logJSONLine(line []byte) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil || fields == nil {
+		l.log(logging.Warning, string(line))
+		return
+	}
+
+	severityKey, messageKey := l.jsonLineSeverityKey, l.jsonLineMessageKey
+	severity := logging.Default
+	if raw, ok := fields[severityKey]; ok {
+		severity = logging.ParseSeverity(jsonString(raw))
+		delete(fields, severityKey)
+	}
+	var msg string
+	if raw, ok := fields[messageKey]; ok {
+		msg = jsonString(raw)
+		delete(fields, messageKey)
+	}
+	details := make([]string, 0, 2*len(fields))
+	for k, raw := range fields {
+		details = append(details, k, jsonString(raw))
+	}
+	l.log(severity, msg, details...)
+}
+
+// jsonString returns raw unquoted if it is a JSON string, or as is otherwise.
+func jsonString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
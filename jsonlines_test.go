@@ -0,0 +1,89 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestJSONLineWriter(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		writes []string
+		want   []Entry
+	}{
+		{
+			name:   "valid line",
+			writes: []string{`{"severity":"ERROR","message":"failed","code":42,"user":"alice"}` + "\n"},
+			want:   []Entry{{Severity: logging.Error, Message: "failed", Details: map[string]string{"code": "42", "user": "alice"}}},
+		},
+		{
+			name:   "invalid lines",
+			writes: []string{"not json\n[1,2]\n"},
+			want: []Entry{
+				{Severity: logging.Warning, Message: "not json", Details: map[string]string{}},
+				{Severity: logging.Warning, Message: "[1,2]", Details: map[string]string{}},
+			},
+		},
+		{
+			name:   "split across writes",
+			writes: []string{`{"severity":"INFO",`, `"message":"hel`, `lo"}` + "\n" + `{"message":"next"}` + "\n"},
+			want: []Entry{
+				{Severity: logging.Info, Message: "hello", Details: map[string]string{}},
+				{Severity: logging.Default, Message: "next", Details: map[string]string{}},
+			},
+		},
+		{
+			name:   "partial line",
+			writes: []string{`{"message":"done"}` + "\n" + `{"message":"not yet"`},
+			want:   []Entry{{Severity: logging.Default, Message: "done", Details: map[string]string{}}},
+		},
+		{
+			name:   "blank lines",
+			writes: []string{"\n  \n"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLogger(io.Discard)
+			w := l.JSONLineWriter()
+			entries := l.Capture(func() {
+				for _, p := range tt.writes {
+					if n, err := w.Write([]byte(p)); n != len(p) || err != nil {
+						t.Fatalf("Write = %d, %v", n, err)
+					}
+				}
+			})
+			for i := range entries {
+				entries[i].Labels = nil
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("logged %+v, want %+v", entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONLineWriterDropsOverlongLine(t *testing.T) {
+	l := newTestLogger(io.Discard)
+	w := l.JSONLineWriter()
+	chunk := strings.Repeat("x", 64*1024)
+	entries := l.Capture(func() {
+		w.Write([]byte(`{"message":"`))
+		for i := 0; i < 8; i++ {
+			w.Write([]byte(chunk))
+		}
+		w.Write([]byte(`"}` + "\n" + `{"message":"after"}` + "\n"))
+	})
+	if len(entries) != 1 || entries[0].Message != "after" {
+		t.Errorf("logged %+v, want only the line after the overlong one", entries)
+	}
+	if got := l.Stats().OverlongJSONLinesDropped; got != 1 {
+		t.Errorf("OverlongJSONLinesDropped = %d, want 1", got)
+	}
+	if buffered := len(w.(*jsonLineWriter).partial); buffered != 0 {
+		t.Errorf("%d bytes still buffered", buffered)
+	}
+}
@@ -0,0 +1,106 @@
+package cloudlogging
+
+import (
+	"strings"
+	"unicode"
+)
+
+// KeyCase is the casing applied to detail keys by WithKeyCase.
+type KeyCase int
+
+const (
+	// AsIs leaves detail keys unchanged.
+	AsIs KeyCase = iota
+	// Snake converts detail keys to snake_case, e.g. "userID" to "user_id".
+	Snake
+	// Camel converts detail keys to camelCase, e.g. "user_id" to "userId".
+	Camel
+)
+
+// WithKeyCase converts the keys of the details passed to the logging methods
+// to style. The "msg" key and the fields added by options are left alone. If
+// two keys convert to the same one, the key already in style wins.
+func WithKeyCase(style KeyCase) Option {
+	return func(l *Logger) {
+		l.keyCase = style
+	}
+}
+
+func (l *Logger) convertKeys(data map[string]string) {
+	if l.keyCase == AsIs {
+		return
+	}
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	for _, k := range keys {
+		if k == "msg" {
+			continue
+		}
+		converted := l.keyCase.convert(k)
+		if converted == k {
+			continue
+		}
+		if _, ok := data[converted]; !ok {
+			data[converted] = data[k]
+		}
+		delete(data, k)
+	}
+}
+
+func (c KeyCase) convert(key string) string {
+	switch c {
+	case Snake:
+		return snakeCase(key)
+	case Camel:
+		return camelCase(key)
+	}
+	return key
+}
+
+// snakeCase starts a new word at each separator, at each upper case letter
+// following a lower case letter or digit, and at the last letter of a run of
+// upper case letters followed by a lower case one, so "HTTPServer" becomes
+// "http_server".
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if r == '-' || r == ' ' || r == '.' {
+			r = '_'
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// camelCase joins the words between separators, upper casing the first letter
+// of each word but the first, so "user_id" becomes "userId". Letters inside a
+// word keep their case, so keys already in camelCase are unchanged.
+func camelCase(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' ' || r == '.'
+	})
+	var b strings.Builder
+	for i, word := range words {
+		runes := []rune(word)
+		if i == 0 {
+			runes[0] = unicode.ToLower(runes[0])
+		} else {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		b.WriteString(string(runes))
+	}
+	if b.Len() == 0 {
+		return s
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package cloudlogging
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSnakeCase(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"userID", "user_id"},
+		{"userId", "user_id"},
+		{"UserName", "user_name"},
+		{"HTTPServer", "http_server"},
+		{"ID", "id"},
+		{"http2Client", "http2_client"},
+		{"user-name", "user_name"},
+		{"user.name", "user_name"},
+		{"user name", "user_name"},
+		{"user_id", "user_id"},
+		{"", ""},
+	} {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := snakeCase(tt.in); got != tt.want {
+				t.Errorf("snakeCase(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"user_id", "userId"},
+		{"user-name", "userName"},
+		{"user.name", "userName"},
+		{"User_Name", "userName"},
+		{"_user__id_", "userId"},
+		{"userID", "userID"},
+		{"userId", "userId"},
+		{"___", "___"},
+		{"", ""},
+	} {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := camelCase(tt.in); got != tt.want {
+				t.Errorf("camelCase(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertKeysCollision(t *testing.T) {
+	l := newTestLogger(io.Discard, WithKeyCase(Snake))
+	data := map[string]string{"msg": "hello", "userId": "camel", "user_id": "snake"}
+	l.convertKeys(data)
+	if len(data) != 2 || data["msg"] != "hello" || data["user_id"] != "snake" {
+		t.Errorf("convertKeys = %v, want msg and the snake_case user_id", data)
+	}
+}
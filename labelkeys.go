@@ -0,0 +1,94 @@
+package cloudlogging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LabelKey is a well-known label key whose values can be validated before
+// they are set, instead of repeating magic strings.
+type LabelKey string
+
+const (
+	LabelService       LabelKey = "service"
+	LabelVersion       LabelKey = "version"
+	LabelEnvironment   LabelKey = "environment"
+	LabelRequestID     LabelKey = "request_id"
+	LabelCorrelationID LabelKey = correlationLabel
+	// LabelTraceID holds a W3C trace ID: 32 lower case hex digits, not all
+	// zero.
+	LabelTraceID LabelKey = "trace_id"
+	// LabelSpanID holds a W3C span ID: 16 lower case hex digits, not all
+	// zero.
+	LabelSpanID LabelKey = "span_id"
+)
+
+var wellKnownLabels = []LabelKey{
+	LabelService, LabelVersion, LabelEnvironment, LabelRequestID,
+	LabelCorrelationID, LabelTraceID, LabelSpanID,
+}
+
+// Validate returns an error if value is malformed for k. Values of the
+// well-known keys must not be empty, and trace and span IDs must be in W3C
+// format; other keys accept any value within the label length limit.
+func (k LabelKey) Validate(value string) error {
+	if len(value) > defaultMaxLabelValueLength {
+		return fmt.Errorf("cloudlogging: label %q: value longer than %d bytes", k, defaultMaxLabelValueLength)
+	}
+	switch k {
+	case LabelService, LabelVersion, LabelEnvironment, LabelRequestID, LabelCorrelationID:
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("cloudlogging: label %q: empty value", k)
+		}
+	case LabelTraceID:
+		if !isHex(value, 32) || value == strings.Repeat("0", 32) {
+			return fmt.Errorf("cloudlogging: label %q: %q is not a 32 digit hex trace ID", k, value)
+		}
+	case LabelSpanID:
+		if !isHex(value, 16) || value == strings.Repeat("0", 16) {
+			return fmt.Errorf("cloudlogging: label %q: %q is not a 16 digit hex span ID", k, value)
+		}
+	}
+	return nil
+}
+
+// Pair validates value and returns it with k as a key/value pair, ready for
+// WithLabels:
+//
+//	pair, err := cloudlogging.LabelService.Pair("billing")
+//	...
+//	l, err := cloudlogging.New(ctx, project, name, backup, cloudlogging.WithLabels(pair...))
+func (k LabelKey) Pair(value string) ([]string, error) {
+	if err := k.Validate(value); err != nil {
+		return nil, err
+	}
+	return []string{string(k), value}, nil
+}
+
+// validateLabels returns the error of the first malformed value of a
+// well-known key among labels.
+func validateLabels(labels map[string]string) error {
+	for _, key := range wellKnownLabels {
+		if value, ok := labels[string(key)]; ok {
+			if err := key.Validate(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dropInvalidLabels removes the malformed values of well-known keys from the
+// common labels, reporting each to the backup logger.
+func (l *Logger) dropInvalidLabels() {
+	for _, key := range wellKnownLabels {
+		value, ok := l.commonLabels[string(key)]
+		if !ok {
+			continue
+		}
+		if err := key.Validate(value); err != nil {
+			delete(l.commonLabels, string(key))
+			l.backup.Printf("%v; label left out", err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestLabelKeyValidate(t *testing.T) {
+	for _, tt := range []struct {
+		key   LabelKey
+		value string
+		ok    bool
+	}{
+		{LabelService, "billing", true},
+		{LabelService, " ", false},
+		{LabelEnvironment, "", false},
+		{LabelTraceID, "4bf92f3577b34da6a3ce929d0e0e4736", true},
+		{LabelTraceID, "4BF92F3577B34DA6A3CE929D0E0E4736", false},
+		{LabelTraceID, "4bf92f35", false},
+		{LabelTraceID, strings.Repeat("0", 32), false},
+		{LabelSpanID, "00f067aa0ba902b7", true},
+		{LabelSpanID, "00f067aa0ba902bz", false},
+		{LabelSpanID, strings.Repeat("0", 16), false},
+		{LabelKey("custom"), "", true},
+		{LabelKey("custom"), strings.Repeat("x", defaultMaxLabelValueLength+1), false},
+	} {
+		err := tt.key.Validate(tt.value)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s.Validate(%.40q) = %v, want ok %v", tt.key, tt.value, err, tt.ok)
+		}
+		if _, err := tt.key.Pair(tt.value); (err == nil) != tt.ok {
+			t.Errorf("%s.Pair(%.40q) = %v, want ok %v", tt.key, tt.value, err, tt.ok)
+		}
+	}
+}
+
+func TestNewValidatesLabels(t *testing.T) {
+	t.Setenv("TESTLABEL_SPAN_ID", "not-a-span")
+	for _, tt := range []struct {
+		name string
+		opt  Option
+		ok   bool
+	}{
+		{"valid", WithLabels("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "team", "billing"), true},
+		{"malformed trace ID", WithLabels("trace_id", "abc"), false},
+		{"empty service", WithLabels("service", ""), false},
+		{"malformed environment label", WithEnvLabels("TESTLABEL_"), false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(context.Background(), "test-project", "test", log.New(io.Discard, "", 0),
+				WithLogfmtSink(io.Discard), tt.opt)
+			if (err == nil) != tt.ok {
+				t.Errorf("New = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNamedDropsInvalidLabels(t *testing.T) {
+	var backup bytes.Buffer
+	l := newTestLogger(&backup)
+	named := l.Named("child", WithLabels("trace_id", "abc", "team", "billing"))
+	if _, ok := named.commonLabels["trace_id"]; ok {
+		t.Error("malformed trace_id kept")
+	}
+	if named.commonLabels["team"] != "billing" {
+		t.Errorf("labels = %v, want team kept", named.commonLabels)
+	}
+	if !strings.Contains(backup.String(), "trace_id") {
+		t.Errorf("backup = %q, want the dropped label reported", backup.String())
+	}
+}
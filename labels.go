@@ -0,0 +1,63 @@
+package cloudlogging
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"cloud.google.com/go/logging"
+)
+
+const (
+	// defaultMaxLabelValueLength is the Cloud Logging limit on label values.
+	defaultMaxLabelValueLength = 64 * 1024
+
+	truncatedMarker = "...(truncated)"
+)
+
+// WithMaxLabelValueLength sets the maximum length in bytes of label values,
+// common and per entry. Longer values are truncated and marked, since Cloud
+// Logging rejects entries with overlong labels. The first truncation is
+// reported with a Warning entry. It defaults to the Cloud Logging limit of
+// 64 KiB; zero or less disables the check.
+func WithMaxLabelValueLength(n int) Option {
+	return func(l *Logger) {
+		l.maxLabelValueLength = n
+	}
+}
+
+// limitLabels truncates overlong label values in place and reports whether it
+// did.
+func (l *Logger) limitLabels(labels map[string]string) bool {
+	limit := l.maxLabelValueLength
+	if limit <= 0 {
+		return false
+	}
+	truncated := false
+	for k, v := range labels {
+		if len(v) > limit {
+			labels[k] = truncate(v, limit)
+			truncated = true
+		}
+	}
+	return truncated
+}
+
+// truncate shortens s to at most limit bytes, including the truncation marker,
+// without splitting a UTF-8 sequence.
+func truncate(s string, limit int) string {
+	n := limit - len(truncatedMarker)
+	if n <= 0 {
+		return truncatedMarker[:limit]
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + truncatedMarker
+}
+
+func (l *Logger) warnLabelsTruncated() {
+	l.labelWarning.Do(func() {
+		data := payload("label values truncated", "max_length", strconv.Itoa(l.maxLabelValueLength))
+		l.deliver(logging.Entry{Payload: data, Severity: logging.Warning}, data)
+	})
+}
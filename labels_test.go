@@ -0,0 +1,87 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestTruncate(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		s     string
+		limit int
+		want  string
+	}{
+		{"ascii", "abcdefghijklmnopqrstuvwxyz", 20, "abcdef" + truncatedMarker},
+		{"limit of the marker", "abcdefghijklmnopqrstuvwxyz", len(truncatedMarker), truncatedMarker},
+		{"limit below the marker", "abcdefghijklmnopqrstuvwxyz", 3, truncatedMarker[:3]},
+		{"multi-byte rune at the cut", "aé" + strings.Repeat("x", 20), 16, "a" + truncatedMarker},
+		{"multi-byte rune before the cut", "éa" + strings.Repeat("x", 20), 17, "éa" + truncatedMarker},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.s, tt.limit)
+			if got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.limit, got, tt.want)
+			}
+			if len(got) > tt.limit {
+				t.Errorf("truncate(%q, %d) is %d bytes", tt.s, tt.limit, len(got))
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncate(%q, %d) = %q is not valid UTF-8", tt.s, tt.limit, got)
+			}
+		})
+	}
+}
+
+func TestOverlongLabelsTruncatedOnEntries(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	var out bytes.Buffer
+	l, err := New(context.Background(), "test-project", "test", log.New(io.Discard, "", 0),
+		WithLogfmtSink(&out), WithMaxLabelValueLength(32), WithLabels("common", long))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Entry().Severity(logging.Info).Message("first").Label("entry", long).Send()
+	l.Entry().Severity(logging.Info).Message("second").Label("entry", long).Send()
+
+	want := strings.Repeat("x", 32-len(truncatedMarker)) + truncatedMarker
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want a warning and 2 entries:\n%s", len(lines), out.String())
+	}
+	if n := strings.Count(out.String(), "label values truncated"); n != 1 {
+		t.Errorf("truncation warning logged %d times, want once:\n%s", n, out.String())
+	}
+	if !strings.Contains(lines[0], "severity=WARNING") {
+		t.Errorf("first line = %q, want the truncation warning", lines[0])
+	}
+	for _, line := range lines[1:] {
+		line += " "
+		for _, label := range []string{"label.common=", "label.entry="} {
+			if !strings.Contains(line, label+want+" ") {
+				t.Errorf("line %q lacks %s with the truncated value", line, label)
+			}
+		}
+	}
+}
+
+func TestEnvLabels(t *testing.T) {
+	t.Setenv("LOGLABEL_TEAM", "billing")
+	t.Setenv("LOGLABEL_Cost_Center", "cc-42")
+	t.Setenv("LOGLABEL_", "ignored")
+	t.Setenv("OTHER_TEAM", "ignored")
+
+	l := newTestLogger(io.Discard, WithEnvLabels("LOGLABEL_"))
+	want := map[string]string{"team": "billing", "cost_center": "cc-42"}
+	if !reflect.DeepEqual(l.commonLabels, want) {
+		t.Errorf("common labels = %v, want %v", l.commonLabels, want)
+	}
+}
@@ -0,0 +1,128 @@
+package cloudlogging
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"cloud.google.com/go/logging"
+)
+
+type logfmtSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithLogfmtSink writes every entry as a logfmt line to w instead of using the
+// Cloud Logging client, for pipelines that collect logfmt. A line reads
+// "time=... severity=... msg=..." followed by the details in key order, the
+// labels as "label.<key>=..." and the trace if any. Details named time or
+// severity are left out, as with WithStdoutJSON. Values with spaces, quotes,
+// equal signs or control characters are quoted. No client is created in this
+// mode.
+func WithLogfmtSink(w io.Writer) Option {
+	return func(l *Logger) {
+		l.logfmt = &logfmtSink{w: w}
+	}
+}
+
+func (l *Logger) writeLogfmt(entry logging.Entry, backupData interface{}) {
+	var b strings.Builder
+	ts := entry.Timestamp
+	if ts.IsZero() {
+		ts = l.now()
+	}
+	writeLogfmtPair(&b, "time", ts.Format(time.RFC3339Nano))
+	writeLogfmtPair(&b, "severity", strings.ToUpper(entry.Severity.String()))
+
+	fields := make(map[string]string)
+	switch data := backupData.(type) {
+	case map[string]string:
+		for k, v := range data {
+			fields[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range data {
+			fields[k] = fmt.Sprint(v)
+		}
+	case string:
+		fields["msg"] = data
+	}
+	if msg, ok := fields["msg"]; ok {
+		writeLogfmtPair(&b, "msg", msg)
+		delete(fields, "msg")
+	}
+	// The entry's own time and severity take these keys.
+	delete(fields, "time")
+	delete(fields, "severity")
+	writeLogfmtMap(&b, "", fields)
+
+	labels := copyMap(l.commonLabels)
+	for k, v := range entry.Labels {
+		labels[k] = v
+	}
+	writeLogfmtMap(&b, "label.", labels)
+	if entry.Trace != "" {
+		writeLogfmtPair(&b, "trace", entry.Trace)
+		writeLogfmtPair(&b, "span_id", entry.SpanID)
+	}
+	b.WriteByte('\n')
+
+	l.logfmt.mu.Lock()
+	defer l.logfmt.mu.Unlock()
+	io.WriteString(l.logfmt.w, b.String())
+}
+
+func writeLogfmtMap(b *strings.Builder, prefix string, m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeLogfmtPair(b, prefix+k, m[k])
+	}
+}
+
+func writeLogfmtPair(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(logfmtKey(key))
+	b.WriteByte('=')
+	if logfmtNeedsQuote(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+// logfmtKey replaces the characters a logfmt key cannot hold with '_'.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func logfmtNeedsQuote(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r == '=' || r == '"' || r == '\\' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
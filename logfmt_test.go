@@ -0,0 +1,33 @@
+package cloudlogging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteLogfmtPair(t *testing.T) {
+	for _, tt := range []struct {
+		name, key, value, want string
+	}{
+		{"plain", "user", "alice", "user=alice"},
+		{"empty value", "user", "", `user=""`},
+		{"space", "msg", "order placed", `msg="order placed"`},
+		{"equal sign", "query", "a=b", `query="a=b"`},
+		{"quote", "msg", `say "hi"`, `msg="say \"hi\""`},
+		{"backslash", "path", `C:\tmp`, `path="C:\\tmp"`},
+		{"newline", "msg", "line1\nline2", `msg="line1\nline2"`},
+		{"control character", "msg", "a\x00b", `msg="a\x00b"`},
+		{"unicode", "city", "Zürich", "city=Zürich"},
+		{"key with space", "user name", "alice", "user_name=alice"},
+		{"key with equal sign and quote", `a="b`, "c", "a__b=c"},
+		{"empty key", "", "c", "_=c"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			writeLogfmtPair(&b, tt.key, tt.value)
+			if got := b.String(); got != tt.want {
+				t.Errorf("writeLogfmtPair(%q, %q) = %s, want %s", tt.key, tt.value, got, tt.want)
+			}
+		})
+	}
+}
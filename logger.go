@@ -4,8 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"cloud.google.com/go/logging"
+	"google.golang.org/api/option"
+	mrpb "google.golang.org/genproto/googleapis/api/monitoredres"
 )
 
 type ILogger interface {
@@ -13,40 +19,278 @@ type ILogger interface {
 	Warn(string, ...string)
 	Info(string, ...string)
 	Debug(string, ...string)
+	Default(string, ...string)
+	Logf(logging.Severity, string, ...any)
 }
 
 type Logger struct {
+	pending int64 // entries not yet flushed; accessed atomically, kept first for alignment
+	entries int64 // accessed atomically
+	mode    int32 // accessed atomically
+	flushes int32 // state of requestFlush; accessed atomically
+
+	settings
+
 	systemCtx context.Context
-	logger    *logging.Logger
+	started   time.Time
+	projectID string
+	name      string
+	conn      *connection
 	backup    *log.Logger
+	child     bool
+
+	loggerMu  sync.RWMutex
+	logger    *logging.Logger
+	loggerGen int // the connection generation logger was created from
+
+	fileBackup *rotatingFile
+
+	subMu      sync.Mutex
+	subLoggers map[string]*logging.Logger
+	subGen     int // the connection generation subLoggers were created from
+	dynamic    dynamicLogs
+
+	capMu    sync.Mutex
+	captures []*[]Entry
+
+	labelWarning sync.Once
+	utf8Warning  sync.Once
+	stats        stats
+
+	spike    spikeAlert
+	dampener dampener
+	throttle throttle
+	watchdog watchdog
+	pause    pause
+	bg       background
+
+	closeOnce   sync.Once
+	closeResult CloseResult
+	closeErr    error
+
+	errMu        sync.Mutex
+	firstErr     error
+	lastErr      error
+	errorHistory []DeliveryError
+}
+
+// settings holds everything configured through options. It is copied into
+// loggers created by Named.
+type settings struct {
+	postCancel   *log.Logger
+	console      *log.Logger
+	stdout       *jsonSink
+	logfmt       *logfmtSink
+	commonLabels map[string]string
+	schemaFields []string
+	keyPrefix    string
+	keyCase      KeyCase
+	fields       map[string]string
+	now          func() time.Time
+	minSeverity  logging.Severity
+	closeMode    CloseMode
+	closeMessage []string
+	goroutineID  bool
+	minimal      bool
+
+	stackFormatter  func(pc []uintptr) string
+	modeHook        func(from, to Mode)
+	compareSeverity func(a, b logging.Severity) int
+	severityDetail  string
+	schemaVersion   string
+	requestIDLabel  string
+	deadlineField   bool
+	requireMessage  bool
+	msgPlaceholder  string
+	sanitizeUTF8    bool
+	nameInBackup    bool
+	requestID       func(context.Context) string
+
+	maxLabelValueLength int
+	errorHistorySize    int
+	deliveryMetrics     bool
+
+	severityLogNames map[logging.Severity]string
+	promoteLabels    []string
+	resource         *mrpb.MonitoredResource
+	severityHooks    []severityHook
+
+	flushOnSeverity bool
+	flushSeverity   logging.Severity
+	flushBufferSize int
+	consoleSeverity logging.Severity
+
+	statusSeverity func(code int) logging.Severity
+	severityKey    string
+	severityFormat func(logging.Severity) any
+	jsonEncoder    func(any) ([]byte, error)
+
+	throttleInterval time.Duration
+
+	defaultSeverity logging.Severity
+
+	dampenThreshold int
+	dampenWindow    time.Duration
+	dampenTo        logging.Severity
+
+	dynamicLogLimit int
+	chunkFields     int
+
+	jsonLineSeverityKey string
+	jsonLineMessageKey  string
+
+	heartbeatInterval time.Duration
+	heartbeatDetails  []string
+
+	runtimeStatsInterval time.Duration
+	watchdogWindow       time.Duration
+	pauseBufferSize      int
+
+	spikeLimit  int
+	spikeWindow time.Duration
+	spikeNotify func(count int)
+
+	tracked        bool
+	trackThreshold int
+
+	clientOptions []option.ClientOption // for tests, to reach a fake server
+}
+
+func defaultSettings() settings {
+	return settings{
+		commonLabels: make(map[string]string),
+		fields:       make(map[string]string),
+		now:          time.Now,
+
+		maxLabelValueLength: defaultMaxLabelValueLength,
+		sanitizeUTF8:        true,
+		pauseBufferSize:     defaultPauseBufferSize,
+
+		jsonLineSeverityKey: "severity",
+		jsonLineMessageKey:  "message",
+	}
+}
+
+func (s settings) clone() settings {
+	s.commonLabels = copyMap(s.commonLabels)
+	s.fields = copyMap(s.fields)
+	s.schemaFields = append([]string(nil), s.schemaFields...)
+	s.heartbeatDetails = append([]string(nil), s.heartbeatDetails...)
+	s.promoteLabels = append([]string(nil), s.promoteLabels...)
+	s.severityHooks = append([]severityHook(nil), s.severityHooks...)
+	if s.severityLogNames != nil {
+		names := make(map[logging.Severity]string, len(s.severityLogNames))
+		for severity, name := range s.severityLogNames {
+			names[severity] = name
+		}
+		s.severityLogNames = names
+	}
+	return s
 }
 
 func NewLogger(ctx context.Context, projectID, loggerName string, backup *log.Logger, labels ...string) (ILogger, error) {
-	client, err := logging.NewClient(ctx, fmt.Sprintf("projects/%s", projectID))
-	if err != nil {
+	return New(ctx, projectID, loggerName, backup, WithLabels(labels...))
+}
+
+func New(ctx context.Context, projectID, loggerName string, backup *log.Logger, opts ...Option) (*Logger, error) {
+	result := &Logger{
+		settings:  defaultSettings(),
+		systemCtx: ctx,
+		projectID: projectOf(projectID),
+		name:      loggerName,
+		backup:    backup,
+	}
+	for _, opt := range opts {
+		opt(result)
+	}
+	if err := validateLabels(result.commonLabels); err != nil {
 		return nil, err
 	}
 
-	n := (len(labels) + 1) / 2
-	if len(labels)%2 != 0 {
-		labels = append(labels, "MISSING")
+	truncated := result.limitLabels(result.commonLabels)
+	if result.stdout == nil && result.logfmt == nil {
+		conn, err := newConnection(ctx, parent(projectID), result.clientOptions, result.onError)
+		if err != nil {
+			return nil, err
+		}
+		result.conn = conn
+		result.logger = conn.client.Logger(loggerName, result.loggerOptions()...)
 	}
-	commonLabels := make(map[string]string, n)
-	for i := 0; i < len(labels); i += 2 {
-		commonLabels[labels[i]] = labels[i+1]
+	if truncated {
+		result.warnLabelsTruncated()
 	}
+	result.track()
+	result.started = result.now()
+	result.startHeartbeat()
+	result.startRuntimeStats()
+	result.startWatchdog()
 
-	result := new(Logger)
-
-	logger := client.Logger(loggerName, logging.CommonLabels(commonLabels))
+	return result, nil
+}
 
-	*result = Logger{
-		systemCtx: ctx,
-		logger:    logger,
+// AsBackupOnly returns a logger that writes every entry to backup and never
+// contacts Cloud Logging, for tests and local runs.
+func AsBackupOnly(backup *log.Logger) ILogger {
+	return &Logger{
+		mode:      int32(ModeFallback),
+		settings:  defaultSettings(),
+		systemCtx: context.Background(),
 		backup:    backup,
 	}
+}
 
-	return result, nil
+// Named returns a logger writing to the log loggerName through the same
+// client. It starts from a copy of l's options, to which opts are applied, so
+// for example WithMinSeverity can differ per log. Malformed values of the
+// well-known label keys are left out, as Named cannot fail. Closing a named
+// logger only flushes it; the client is closed by the logger it was created
+// from.
+func (l *Logger) Named(loggerName string, opts ...Option) *Logger {
+	result := &Logger{
+		settings:  l.settings.clone(),
+		systemCtx: l.systemCtx,
+		projectID: l.projectID,
+		name:      loggerName,
+		conn:      l.conn,
+		backup:    l.backup,
+		child:     true,
+	}
+	for _, opt := range opts {
+		opt(result)
+	}
+	result.tracked = false
+	result.dropInvalidLabels()
+	truncated := result.limitLabels(result.commonLabels)
+	if l.conn != nil {
+		client, gen := l.conn.current()
+		result.logger = client.Logger(loggerName, result.loggerOptions()...)
+		result.loggerGen = gen
+	}
+	if truncated {
+		result.warnLabelsTruncated()
+	}
+	return result
+}
+
+func parent(projectID string) string {
+	for _, prefix := range []string{"projects/", "folders/", "organizations/", "billingAccounts/"} {
+		if strings.HasPrefix(projectID, prefix) {
+			return projectID
+		}
+	}
+	return fmt.Sprintf("projects/%s", projectID)
+}
+
+// projectOf returns the bare project ID of a projectID as accepted by
+// NewLogger, or "" when it names a folder, organization or billing account.
+func projectOf(projectID string) string {
+	if !strings.Contains(projectID, "/") {
+		return projectID
+	}
+	if strings.HasPrefix(projectID, "projects/") {
+		return strings.TrimPrefix(projectID, "projects/")
+	}
+	return ""
 }
 
 func payload(msg string, details ...string) map[string]string {
@@ -64,15 +308,183 @@ func payload(msg string, details ...string) map[string]string {
 }
 
 func (l *Logger) log(severity logging.Severity, msg string, details ...string) {
-	data := payload(msg, details...)
+	severity = l.normalizeSeverity(severity)
+	// Filtered entries cost no allocation, unless a detail can still change
+	// their severity.
+	if l.severityDetail == "" && !l.enabled(severity) {
+		return
+	}
+	if l.minimal {
+		l.logMinimal(severity, msg, details...)
+		return
+	}
+	l.logData(severity, payload(msg, details...))
+}
+
+func (l *Logger) logData(severity logging.Severity, data map[string]string) {
+	severity = l.severityFromDetail(severity, data)
+	l.enrich(severity, data)
 	entry := logging.Entry{
 		Payload:  data,
 		Severity: severity,
 	}
-	if isDone(l.systemCtx) {
-		l.backup.Printf("%-10s: %v", severity.String(), data)
+	l.write(entry, data)
+}
+
+// severityFromDetail returns the severity named by the detail set with
+// WithSeverityFromDetail, removing it from data, or severity when it is
+// missing or not a valid severity name.
+func (l *Logger) severityFromDetail(severity logging.Severity, data map[string]string) logging.Severity {
+	if l.severityDetail == "" {
+		return severity
+	}
+	value, ok := data[l.severityDetail]
+	if !ok {
+		return severity
+	}
+	delete(data, l.severityDetail)
+
+	parsed := logging.ParseSeverity(value)
+	if parsed == logging.Default && !strings.EqualFold(strings.TrimSpace(value), "default") {
+		return severity
+	}
+	return parsed
+}
+
+func (l *Logger) enrich(severity logging.Severity, data map[string]string) {
+	l.convertKeys(data)
+	if l.keyPrefix != "" {
+		for k, v := range data {
+			if k != "msg" && !strings.HasPrefix(k, l.keyPrefix) {
+				delete(data, k)
+				data[l.keyPrefix+k] = v
+			}
+		}
+	}
+	l.addStack(severity, data)
+	for k, v := range l.fields {
+		if _, ok := data[k]; !ok {
+			data[k] = v
+		}
+	}
+	if l.goroutineID {
+		if id := goroutineID(); id != "" {
+			data["goid"] = id
+		}
+	}
+	for _, hook := range l.severityHooks {
+		if l.atLeast(severity, hook.minSeverity) {
+			hook.fn(data)
+		}
+	}
+	if l.schemaVersion != "" {
+		data["schema_version"] = l.schemaVersion
+	}
+	for _, field := range l.schemaFields {
+		if _, ok := data[field]; !ok {
+			data[field] = ""
+		}
+	}
+}
+
+func (l *Logger) write(entry logging.Entry, backupData interface{}) {
+	entry.Severity = l.normalizeSeverity(entry.Severity)
+	if !l.checkMessage(backupData) {
+		return
+	}
+	l.sanitize(backupData)
+	l.dampen(&entry, backupData)
+	if !l.enabled(entry.Severity) || l.throttleError(entry, backupData) {
+		return
+	}
+	if l.limitLabels(entry.Labels) {
+		l.warnLabelsTruncated()
+	}
+	l.checkSpike()
+	l.deliverChunked(entry, backupData)
+}
+
+// checkMessage applies WithRequireMessage and WithMessagePlaceholder to the
+// payload and reports whether the entry should be logged.
+func (l *Logger) checkMessage(backupData interface{}) bool {
+	data, ok := backupData.(map[string]string)
+	if !ok || data["msg"] != "" {
+		return true
+	}
+	if l.requireMessage {
+		l.stats.mu.Lock()
+		l.stats.EmptyMessagesDropped++
+		l.stats.mu.Unlock()
+		return false
+	}
+	if l.msgPlaceholder != "" {
+		data["msg"] = l.msgPlaceholder
+	}
+	return true
+}
+
+func (l *Logger) deliver(entry logging.Entry, backupData interface{}) {
+	if l.capture(entry, backupData) {
+		return
+	}
+	atomic.AddInt64(&l.entries, 1)
+	if l.stdout != nil {
+		l.setMode(ModeHealthy)
+		l.writeJSON(entry, backupData)
+		l.echo(entry, backupData)
+	} else if l.logfmt != nil {
+		l.setMode(ModeHealthy)
+		l.writeLogfmt(entry, backupData)
+		l.echo(entry, backupData)
+	} else if l.conn == nil || isDone(l.systemCtx) || l.deliveryStalled() {
+		l.setMode(ModeFallback)
+		backup := l.backup
+		if l.postCancel != nil && isDone(l.systemCtx) {
+			backup = l.postCancel
+		}
+		l.printLine(backup, entry, backupData)
+	} else if l.hold(entry) {
+		l.printLine(l.backup, entry, backupData)
+	} else {
+		l.setMode(ModeHealthy)
+		l.send(entry)
+		l.echo(entry, backupData)
+	}
+}
+
+// send hands entry to the client logger it is routed to.
+func (l *Logger) send(entry logging.Entry) {
+	logger := l.loggerFor(entry.Severity)
+	if entry.LogName != "" {
+		if dynamic := l.dynamicLogger(entry.LogName); dynamic != nil {
+			logger = dynamic
+		} else {
+			labels := copyMap(entry.Labels)
+			labels[dynamicLogLabel] = entry.LogName
+			entry.Labels = labels
+		}
+		entry.LogName = ""
+	}
+	atomic.AddInt64(&l.pending, 1)
+	logger.Log(entry)
+	if l.flushOnSeverity && l.atLeast(entry.Severity, l.flushSeverity) {
+		l.requestFlush()
+	}
+}
+
+// printLine writes entry as a backup line to out.
+func (l *Logger) printLine(out *log.Logger, entry logging.Entry, backupData interface{}) {
+	// fmt prints maps with sorted keys, so backup lines are stable across runs.
+	if l.nameInBackup && l.name != "" {
+		out.Printf("[%s] %-10s: %v", l.name, entry.Severity.String(), backupData)
 	} else {
-		l.logger.Log(entry)
+		out.Printf("%-10s: %v", entry.Severity.String(), backupData)
+	}
+}
+
+func (l *Logger) echo(entry logging.Entry, backupData interface{}) {
+	if l.console != nil && l.atLeast(entry.Severity, l.consoleSeverity) {
+		l.printLine(l.console, entry, backupData)
 	}
 }
 
@@ -92,6 +504,42 @@ func (l *Logger) Debug(msg string, details ...string) {
 	l.log(logging.Debug, msg, details...)
 }
 
+// Default logs at logging.Default, the severity of entries with no assigned
+// level. Default is the lowest severity, so any WithMinSeverity above it
+// drops these entries.
+func (l *Logger) Default(msg string, details ...string) {
+	l.log(logging.Default, msg, details...)
+}
+
+func (l *Logger) Logf(severity logging.Severity, format string, args ...any) {
+	severity = l.normalizeSeverity(severity)
+	if !l.enabled(severity) {
+		return
+	}
+	l.log(severity, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) enabled(severity logging.Severity) bool {
+	return l.atLeast(severity, l.minSeverity)
+}
+
+// atLeast reports whether severity is at or above threshold, in the order
+// set by WithSeverityComparator.
+func (l *Logger) atLeast(severity, threshold logging.Severity) bool {
+	if l.compareSeverity != nil {
+		return l.compareSeverity(severity, threshold) >= 0
+	}
+	return severity >= threshold
+}
+
+func copyMap(m map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
 func isDone(ctx context.Context) bool {
 	select {
 	case <-ctx.Done():
@@ -100,3 +548,53 @@ func isDone(ctx context.Context) bool {
 	}
 	return false
 }
+
+func (l *Logger) onError(err error) {
+	// The client does not say which entries failed.
+	l.recordError(err, logging.Default)
+	l.backup.Printf("logging client: %v", err)
+}
+
+func (l *Logger) recordError(err error, severity logging.Severity) {
+	l.errMu.Lock()
+	defer l.errMu.Unlock()
+
+	if l.firstErr == nil {
+		l.firstErr = err
+	}
+	l.lastErr = err
+	if l.errorHistorySize > 0 {
+		if len(l.errorHistory) == l.errorHistorySize {
+			l.errorHistory = l.errorHistory[1:]
+		}
+		l.errorHistory = append(l.errorHistory, DeliveryError{
+			Err:      err,
+			Time:     l.now(),
+			Severity: severity,
+		})
+	}
+}
+
+// FirstError returns the first delivery error reported by the client, or nil.
+func (l *Logger) FirstError() error {
+	l.errMu.Lock()
+	defer l.errMu.Unlock()
+	return l.firstErr
+}
+
+// LastError returns the most recent delivery error reported by the client, or nil.
+func (l *Logger) LastError() error {
+	l.errMu.Lock()
+	defer l.errMu.Unlock()
+	return l.lastErr
+}
+
+// ClearErrors resets the errors returned by FirstError, LastError and
+// ErrorHistory.
+func (l *Logger) ClearErrors() {
+	l.errMu.Lock()
+	l.firstErr = nil
+	l.lastErr = nil
+	l.errorHistory = nil
+	l.errMu.Unlock()
+}
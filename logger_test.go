@@ -0,0 +1,289 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+// newTestLogger returns a logger without client writing its backup lines to
+// out, with opts applied.
+func newTestLogger(out io.Writer, opts ...Option) *Logger {
+	l := &Logger{
+		mode:      int32(ModeFallback),
+		settings:  defaultSettings(),
+		systemCtx: context.Background(),
+		backup:    log.New(out, "", 0),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+func BenchmarkInfoNoDetails(b *testing.B) {
+	b.Run("enabled", func(b *testing.B) {
+		l := newTestLogger(io.Discard)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info("request served")
+		}
+	})
+	b.Run("filtered", func(b *testing.B) {
+		l := newTestLogger(io.Discard, WithMinSeverity(logging.Warning))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info("request served")
+		}
+	})
+}
+
+func TestParent(t *testing.T) {
+	for _, tt := range []struct {
+		projectID, parent, project string
+	}{
+		{"my-project", "projects/my-project", "my-project"},
+		{"projects/my-project", "projects/my-project", "my-project"},
+		{"folders/123", "folders/123", ""},
+		{"organizations/456", "organizations/456", ""},
+		{"billingAccounts/0A1B2C", "billingAccounts/0A1B2C", ""},
+	} {
+		t.Run(tt.projectID, func(t *testing.T) {
+			if got := parent(tt.projectID); got != tt.parent {
+				t.Errorf("parent(%q) = %q, want %q", tt.projectID, got, tt.parent)
+			}
+			if got := projectOf(tt.projectID); got != tt.project {
+				t.Errorf("projectOf(%q) = %q, want %q", tt.projectID, got, tt.project)
+			}
+		})
+	}
+}
+
+func TestNamedMinSeverity(t *testing.T) {
+	parent := newTestLogger(io.Discard, WithMinSeverity(logging.Warning))
+	audit := parent.Named("audit", WithMinSeverity(logging.Debug))
+	errs := parent.Named("errors", WithMinSeverity(logging.Error))
+
+	for _, tt := range []struct {
+		logger *Logger
+		want   []string
+	}{
+		{parent, []string{"warn", "error"}},
+		{audit, []string{"debug", "info", "warn", "error"}},
+		{errs, []string{"error"}},
+	} {
+		l := tt.logger
+		entries := l.Capture(func() {
+			l.Debug("debug")
+			l.Info("info")
+			l.Warn("warn")
+			l.Error("error")
+		})
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.Message)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q logged %v, want %v", l.name, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaFields(t *testing.T) {
+	l := newTestLogger(io.Discard, WithSchemaFields("user", "region"))
+	entries := l.Capture(func() {
+		l.Info("no fields")
+		l.Info("with user", "user", "bob")
+	})
+
+	for i, want := range []map[string]string{
+		{"user": "", "region": ""},
+		{"user": "bob", "region": ""},
+	} {
+		for field, value := range want {
+			got, ok := entries[i].Details[field]
+			if !ok || got != value {
+				t.Errorf("entry %d: %s = %q (present %t), want %q", i, field, got, ok, value)
+			}
+		}
+	}
+}
+
+func TestBackupKeyOrder(t *testing.T) {
+	var out bytes.Buffer
+	l := newTestLogger(&out)
+	for i := 0; i < 5; i++ {
+		l.Info("request served", "user", "alice", "method", "GET", "b", "2", "a", "1", "status", "200")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	want := "Info      : map[a:1 b:2 method:GET msg:request served status:200 user:alice]"
+	for i, line := range lines {
+		if line != want {
+			t.Errorf("line %d = %q, want %q", i, line, want)
+		}
+	}
+}
+
+func TestNilDetailsAndLabels(t *testing.T) {
+	var details, labels []string
+	parent := newTestLogger(io.Discard, WithLabels(labels...))
+	l := parent.Named("child", WithLabels(labels...))
+
+	entries := l.Capture(func() {
+		l.Error("error", details...)
+		l.Warn("warn", details...)
+		l.Info("info", details...)
+		l.Debug("debug", details...)
+		l.Default("default", details...)
+		l.Logf(logging.Notice, "notice")
+	})
+	if len(entries) != 6 {
+		t.Fatalf("captured %d entries, want 6", len(entries))
+	}
+	for _, e := range entries {
+		if len(e.Details) != 0 || len(e.Labels) != 0 {
+			t.Errorf("%q has details %v and labels %v, want none", e.Message, e.Details, e.Labels)
+		}
+	}
+}
+
+func TestKeyPrefix(t *testing.T) {
+	parent := newTestLogger(io.Discard)
+	billing := parent.Named("app", WithKeyPrefix("billing."))
+
+	entries := billing.Capture(func() {
+		billing.Info("invoice sent", "id", "inv-1", "billing.amount", "12")
+	})
+	if got := entries[0].Message; got != "invoice sent" {
+		t.Errorf("message = %q, want it unprefixed", got)
+	}
+	if want := map[string]string{"billing.id": "inv-1", "billing.amount": "12"}; !reflect.DeepEqual(entries[0].Details, want) {
+		t.Errorf("details = %v, want %v", entries[0].Details, want)
+	}
+
+	entries = parent.Capture(func() { parent.Info("unprefixed", "id", "inv-1") })
+	if want := map[string]string{"id": "inv-1"}; !reflect.DeepEqual(entries[0].Details, want) {
+		t.Errorf("parent details = %v, want %v", entries[0].Details, want)
+	}
+}
+
+func TestSeverityFromDetail(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		details []string
+		want    logging.Severity
+	}{
+		{"valid", []string{"level", "error"}, logging.Error},
+		{"upper case", []string{"level", "WARNING"}, logging.Warning},
+		{"default", []string{"level", "default"}, logging.Default},
+		{"invalid", []string{"level", "loud"}, logging.Info},
+		{"missing", []string{"user", "alice"}, logging.Info},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLogger(io.Discard, WithSeverityFromDetail("level"))
+			entries := l.Capture(func() { l.Info("legacy", tt.details...) })
+			if got := entries[0].Severity; got != tt.want {
+				t.Errorf("severity = %v, want %v", got, tt.want)
+			}
+			if value, ok := entries[0].Details["level"]; ok {
+				t.Errorf("level = %q left in the payload", value)
+			}
+		})
+	}
+}
+
+func TestEmptyMessage(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		opts     []Option
+		messages []string
+		dropped  int64
+	}{
+		{"default", nil, []string{"", "served"}, 0},
+		{"drop", []Option{WithRequireMessage(true)}, []string{"served"}, 1},
+		{"placeholder", []Option{WithMessagePlaceholder("(no message)")}, []string{"(no message)", "served"}, 0},
+		{"drop over placeholder", []Option{WithRequireMessage(true), WithMessagePlaceholder("(no message)")}, []string{"served"}, 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLogger(io.Discard, tt.opts...)
+			entries := l.Capture(func() {
+				l.Info("")
+				l.Info("served")
+			})
+
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Message)
+			}
+			if !reflect.DeepEqual(got, tt.messages) {
+				t.Errorf("messages = %q, want %q", got, tt.messages)
+			}
+			if got := l.Stats().EmptyMessagesDropped; got != tt.dropped {
+				t.Errorf("EmptyMessagesDropped = %d, want %d", got, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestLoggerNameInBackup(t *testing.T) {
+	for _, tt := range []struct {
+		enabled bool
+		want    string
+	}{
+		{false, "Info      : map[msg:charged]\n"},
+		{true, "[billing] Info      : map[msg:charged]\n"},
+	} {
+		var out bytes.Buffer
+		l := newTestLogger(&out).Named("billing", WithLoggerNameInBackup(tt.enabled))
+		l.Info("charged")
+		if got := out.String(); got != tt.want {
+			t.Errorf("WithLoggerNameInBackup(%t): backup = %q, want %q", tt.enabled, got, tt.want)
+		}
+	}
+}
+
+func TestAsBackupOnly(t *testing.T) {
+	var out bytes.Buffer
+	il := AsBackupOnly(log.New(&out, "", 0))
+	il.Warn("disk almost full", "free", "2%")
+
+	l := il.(*Logger)
+	if l.conn != nil || l.logger != nil {
+		t.Error("backup-only logger has a client")
+	}
+	if got := l.Mode(); got != ModeFallback {
+		t.Errorf("Mode() = %v, want ModeFallback", got)
+	}
+	if got, want := out.String(), "Warning   : map[free:2% msg:disk almost full]\n"; got != want {
+		t.Errorf("backup = %q, want %q", got, want)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+}
+
+func TestConsoleEcho(t *testing.T) {
+	var console bytes.Buffer
+	srv := &fakeServer{}
+	l := newServedLogger(t, srv, io.Discard, WithConsoleEcho(logging.Warning, &console))
+	l.Info("request served")
+	l.Error("payment failed")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := srv.total(); got != 2 {
+		t.Errorf("server received %d entries, want both", got)
+	}
+	lines := strings.Split(strings.TrimSuffix(console.String(), "\n"), "\n")
+	if len(lines) != 1 || !strings.HasSuffix(lines[0], "Error     : map[msg:payment failed]") {
+		t.Errorf("console = %q, want only the Error entry", console.String())
+	}
+}
@@ -0,0 +1,74 @@
+package cloudlogging
+
+import (
+	"cloud.google.com/go/logging"
+)
+
+// WithSeverityLogNames sends entries of the mapped severities to their own log
+// names through the same client, e.g. Error to "app-errors". Unmapped
+// severities go to the logger's own log.
+func WithSeverityLogNames(names map[logging.Severity]string) Option {
+	return func(l *Logger) {
+		if l.severityLogNames == nil {
+			l.severityLogNames = make(map[logging.Severity]string, len(names))
+		}
+		for severity, name := range names {
+			l.severityLogNames[severity] = name
+		}
+	}
+}
+
+// loggerFor returns the client logger an entry of severity is written to,
+// creating it on first use.
+func (l *Logger) loggerFor(severity logging.Severity) *logging.Logger {
+	name, ok := l.severityLogNames[severity]
+	if !ok {
+		return l.clientLogger()
+	}
+	return l.subLogger(name)
+}
+
+func (l *Logger) subLogger(name string) *logging.Logger {
+	client, gen := l.conn.current()
+	l.subMu.Lock()
+	defer l.subMu.Unlock()
+
+	if l.subGen != gen {
+		l.subLoggers, l.subGen = nil, gen
+	}
+	if logger, ok := l.subLoggers[name]; ok {
+		return logger
+	}
+	if l.subLoggers == nil {
+		l.subLoggers = make(map[string]*logging.Logger)
+	}
+	logger := client.Logger(name, l.loggerOptions()...)
+	l.subLoggers[name] = logger
+	return logger
+}
+
+// flushSubLoggers flushes every logger created by subLogger and
+// dynamicLogger. The client
+// flushes them itself when it is closed, so this is only needed when the
+// client stays open. The loggers are not locked while flushing, which blocks
+// as long as delivery is stalled.
+func (l *Logger) flushSubLoggers() error {
+	l.subMu.Lock()
+	loggers := make([]*logging.Logger, 0, len(l.subLoggers))
+	for _, logger := range l.subLoggers {
+		loggers = append(loggers, logger)
+	}
+	l.subMu.Unlock()
+
+	var firstErr error
+	for _, logger := range loggers {
+		if err := l.flush(logger); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if err := l.flushDynamicLoggers(); firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
@@ -0,0 +1,37 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestCloseFlushesSubLoggers(t *testing.T) {
+	srv := &fakeServer{}
+	l := newServedLogger(t, srv, io.Discard, WithSeverityLogNames(map[logging.Severity]string{
+		logging.Error: "app-errors",
+	}))
+	child := l.Named("child")
+
+	l.Info("info")
+	l.Error("error")
+	child.Info("from child")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, entry := range srv.received() {
+		got[entry.GetJsonPayload().GetFields()["msg"].GetStringValue()] = entry.LogName
+	}
+	want := map[string]string{
+		"info":       "projects/test-project/logs/test",
+		"error":      "projects/test-project/logs/app-errors",
+		"from child": "projects/test-project/logs/child",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("received %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,47 @@
+package cloudlogging
+
+import (
+	"sync"
+
+	"cloud.google.com/go/logging"
+)
+
+var payloadPool = sync.Pool{
+	New: func() interface{} { return make(map[string]string) },
+}
+
+// WithMinimalMode makes the fixed-severity methods, Default and Logf take a
+// short path for resource-constrained deployments: payload maps are pooled
+// and none of the enrichment options apply (fields, schema fields, key prefix,
+// goroutine ID, stack traces, severity hooks, severity from detail). The
+// severity filter, delivery options and backup path still apply. The payload
+// map is reused once the call returns, so every output must serialize or copy
+// it before then, as the client, the stdout, logfmt and backup outputs,
+// Capture, WithErrorThrottle and Pause do.
+func WithMinimalMode() Option {
+	return func(l *Logger) {
+		l.minimal = true
+	}
+}
+
+func (l *Logger) logMinimal(severity logging.Severity, msg string, details ...string) {
+	if !l.enabled(severity) {
+		return
+	}
+
+	data := payloadPool.Get().(map[string]string)
+	data["msg"] = msg
+	for i := 0; i < len(details); i += 2 {
+		if i+1 < len(details) {
+			data[details[i]] = details[i+1]
+		} else {
+			data[details[i]] = "MISSING"
+		}
+	}
+	l.write(logging.Entry{Payload: data, Severity: severity}, data)
+
+	for k := range data {
+		delete(data, k)
+	}
+	payloadPool.Put(data)
+}
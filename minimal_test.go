@@ -0,0 +1,24 @@
+package cloudlogging
+
+import (
+	"io"
+	"testing"
+)
+
+func BenchmarkMinimalMode(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		opts []Option
+	}{
+		{"full", []Option{WithSchemaFields("user", "tenant")}},
+		{"minimal", []Option{WithMinimalMode()}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			l := newTestLogger(io.Discard, bm.opts...)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l.Info("request served", "path", "/orders", "status", "200")
+			}
+		})
+	}
+}
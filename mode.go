@@ -0,0 +1,53 @@
+package cloudlogging
+
+import "sync/atomic"
+
+// Mode is where the logger currently sends entries.
+type Mode int32
+
+const (
+	// ModeHealthy means entries go to Cloud Logging, or to stdout with
+	// WithStdoutJSON or to the WithLogfmtSink writer.
+	ModeHealthy Mode = iota
+	// ModeFallback means entries go to the backup logger, because the system
+	// context is done, there is no client or WithDeliveryWatchdog found
+	// delivery stalled.
+	ModeFallback
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeHealthy:
+		return "Healthy"
+	case ModeFallback:
+		return "Fallback"
+	default:
+		return "Unknown"
+	}
+}
+
+// WithModeChangeHook calls fn whenever the logger switches modes, from the
+// goroutine logging the first entry in the new mode. No lock of the logger is
+// held while fn runs, so fn may log.
+func WithModeChangeHook(fn func(from, to Mode)) Option {
+	return func(l *Logger) {
+		l.modeHook = fn
+	}
+}
+
+// Mode returns the mode of the most recent entry.
+func (l *Logger) Mode() Mode {
+	return Mode(atomic.LoadInt32(&l.mode))
+}
+
+// IsFallback reports whether entries currently go to the backup logger.
+func (l *Logger) IsFallback() bool {
+	return l.Mode() == ModeFallback
+}
+
+func (l *Logger) setMode(mode Mode) {
+	from := Mode(atomic.SwapInt32(&l.mode, int32(mode)))
+	if from != mode && l.modeHook != nil {
+		l.modeHook(from, mode)
+	}
+}
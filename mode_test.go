@@ -0,0 +1,34 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestModeChangeHook(t *testing.T) {
+	var l *Logger
+	var transitions []string
+	hook := func(from, to Mode) {
+		transitions = append(transitions, from.String()+"->"+to.String())
+		l.Info("mode changed", "to", to.String())
+	}
+	srv := &fakeServer{}
+	l = newServedLogger(t, srv, io.Discard, WithModeChangeHook(hook))
+	defer l.Close()
+
+	l.Info("healthy")
+	atomic.StoreInt32(&l.watchdog.stalled, 1)
+	l.Info("stalled")
+	l.Info("still stalled")
+	atomic.StoreInt32(&l.watchdog.stalled, 0)
+	l.Info("recovered")
+
+	if want := []string{"Healthy->Fallback", "Fallback->Healthy"}; !reflect.DeepEqual(transitions, want) {
+		t.Errorf("transitions = %q, want %q", transitions, want)
+	}
+	if l.IsFallback() {
+		t.Error("IsFallback() = true after recovering")
+	}
+}
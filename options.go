@@ -0,0 +1,222 @@
+package cloudlogging
+
+import (
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+type Option func(*Logger)
+
+// readBuildInfo is replaced in tests, whose binaries carry no VCS revision.
+var readBuildInfo = debug.ReadBuildInfo
+
+// WithLabels adds common labels, given as key/value pairs, to every entry.
+// New fails if a well-known key, such as LabelTraceID, has a malformed value;
+// see LabelKey.Validate.
+func WithLabels(labels ...string) Option {
+	return func(l *Logger) {
+		if len(labels)%2 != 0 {
+			labels = append(labels, "MISSING")
+		}
+		for i := 0; i < len(labels); i += 2 {
+			l.commonLabels[labels[i]] = labels[i+1]
+		}
+	}
+}
+
+// WithEnvLabels adds a common label for every environment variable named
+// prefix followed by the label key, e.g. LOGLABEL_TEAM=billing with the prefix
+// "LOGLABEL_" becomes the label team=billing. Keys are lowercased; variables
+// with nothing after the prefix are ignored. Values are validated as with
+// WithLabels.
+func WithEnvLabels(prefix string) Option {
+	return func(l *Logger) {
+		for _, env := range os.Environ() {
+			name, value, ok := strings.Cut(env, "=")
+			if !ok || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			key := strings.ToLower(strings.TrimPrefix(name, prefix))
+			if key == "" {
+				continue
+			}
+			l.commonLabels[key] = value
+		}
+	}
+}
+
+// WithClock replaces time.Now for the time-based features of the logger.
+func WithClock(now func() time.Time) Option {
+	return func(l *Logger) {
+		l.now = now
+	}
+}
+
+// WithPostCancelWriter sends backup output to w once the system context is
+// canceled, for when the regular backup destination is closed at shutdown.
+// The backup logger's prefix and flags are kept.
+func WithPostCancelWriter(w io.Writer) Option {
+	return func(l *Logger) {
+		prefix, flags := "", log.LstdFlags
+		if l.backup != nil {
+			prefix, flags = l.backup.Prefix(), l.backup.Flags()
+		}
+		l.postCancel = log.New(w, prefix, flags)
+	}
+}
+
+// WithSchemaFields makes every entry's payload contain the given fields,
+// defaulting to an empty string, so Log Analytics sees a consistent column
+// set. This slightly increases the size of every entry.
+func WithSchemaFields(fields ...string) Option {
+	return func(l *Logger) {
+		l.schemaFields = append(l.schemaFields, fields...)
+	}
+}
+
+// WithBuildInfo adds the VCS revision the binary was built from as a
+// "commit" field on every entry. Nothing is added when the binary carries no
+// build info or no revision.
+func WithBuildInfo() Option {
+	return func(l *Logger) {
+		info, ok := readBuildInfo()
+		if !ok {
+			return
+		}
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && setting.Value != "" {
+				l.fields["commit"] = setting.Value
+			}
+		}
+	}
+}
+
+// WithMinSeverity drops entries below severity. Severities are ordered by
+// their numeric value, in which Default (0) is below Debug, so Default entries
+// are dropped by any minimum other than Default itself.
+func WithMinSeverity(severity logging.Severity) Option {
+	return func(l *Logger) {
+		l.minSeverity = severity
+	}
+}
+
+// WithSeverityComparator replaces the numeric ordering of severities used by
+// the severity thresholds, such as WithMinSeverity, WithFlushOnSeverity and the
+// Error threshold of stack traces. compare returns a negative number when a
+// ranks below b, zero when they rank the same and a positive number otherwise.
+func WithSeverityComparator(compare func(a, b logging.Severity) int) Option {
+	return func(l *Logger) {
+		l.compareSeverity = compare
+	}
+}
+
+// WithFlushOnSeverity starts a flush in the background right after an entry
+// at or above severity is logged, so important entries are not held back by
+// buffering. Logging itself stays asynchronous.
+func WithFlushOnSeverity(severity logging.Severity) Option {
+	return func(l *Logger) {
+		l.flushOnSeverity = true
+		l.flushSeverity = severity
+	}
+}
+
+// WithHostMetadata adds the hostname, process ID and OS, read once here, as
+// "hostname", "pid" and "os" fields on every entry. The hostname is left out
+// when it cannot be determined.
+func WithHostMetadata() Option {
+	return func(l *Logger) {
+		if hostname, err := os.Hostname(); err == nil {
+			l.fields["hostname"] = hostname
+		}
+		l.fields["pid"] = strconv.Itoa(os.Getpid())
+		l.fields["os"] = runtime.GOOS
+	}
+}
+
+// WithKeyPrefix prefixes the detail keys of every entry, but not the message,
+// so that subsystems sharing a log get their own namespace:
+//
+//	billing := l.Named("app", WithKeyPrefix("billing."))
+func WithKeyPrefix(prefix string) Option {
+	return func(l *Logger) {
+		l.keyPrefix = prefix
+	}
+}
+
+// WithSeverityFromDetail takes the severity of entries from the detail key,
+// parsed with logging.ParseSeverity, and removes it from the payload. It
+// eases migrating adapters that encode the severity in their data. Entries
+// with a missing or invalid value keep the severity of the method used.
+func WithSeverityFromDetail(key string) Option {
+	return func(l *Logger) {
+		l.severityDetail = key
+	}
+}
+
+// WithRequireMessage drops entries with an empty message when enabled. Dropped
+// entries are counted in Stats.EmptyMessagesDropped, which helps tracking
+// down accidental empty log calls.
+func WithRequireMessage(enabled bool) Option {
+	return func(l *Logger) {
+		l.requireMessage = enabled
+	}
+}
+
+// WithMessagePlaceholder replaces empty messages with placeholder, such as
+// "(no message)". WithRequireMessage takes precedence.
+func WithMessagePlaceholder(placeholder string) Option {
+	return func(l *Logger) {
+		l.msgPlaceholder = placeholder
+	}
+}
+
+// WithLoggerNameInBackup prefixes backup lines with the logger name, e.g.
+// "[audit] ", to tell apart Named loggers sharing a backup logger.
+func WithLoggerNameInBackup(enabled bool) Option {
+	return func(l *Logger) {
+		l.nameInBackup = enabled
+	}
+}
+
+type severityHook struct {
+	minSeverity logging.Severity
+	fn          func(details map[string]string)
+}
+
+// WithSeverityHook calls fn with the details of every entry at or above
+// minSeverity, after the logger has added its own fields, so fn can enrich
+// them, for example with a runbook link on Error entries. Hooks run in the
+// order they were added.
+func WithSeverityHook(minSeverity logging.Severity, fn func(details map[string]string)) Option {
+	return func(l *Logger) {
+		l.severityHooks = append(l.severityHooks, severityHook{minSeverity, fn})
+	}
+}
+
+// WithConsoleEcho also writes entries at or above minSeverity to w, in the
+// backup line format, while they are delivered normally. Unlike the backup
+// logger, which only receives entries Cloud Logging cannot, the echo keeps a
+// local view of the important entries.
+func WithConsoleEcho(minSeverity logging.Severity, w io.Writer) Option {
+	return func(l *Logger) {
+		l.console = log.New(w, "", log.LstdFlags)
+		l.consoleSeverity = minSeverity
+	}
+}
+
+// WithSchemaVersion adds version as a "schema_version" field to every entry,
+// so consumers can tell log schema generations apart during rollouts. It
+// cannot be overridden by details.
+func WithSchemaVersion(version string) Option {
+	return func(l *Logger) {
+		l.schemaVersion = version
+	}
+}
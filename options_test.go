@@ -0,0 +1,111 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestBuildInfo(t *testing.T) {
+	defer func(read func() (*debug.BuildInfo, bool)) { readBuildInfo = read }(readBuildInfo)
+
+	for _, tt := range []struct {
+		name   string
+		info   *debug.BuildInfo
+		ok     bool
+		commit string
+	}{
+		{"revision", &debug.BuildInfo{Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: "0a1b2c3"}}}, true, "0a1b2c3"},
+		{"no revision", &debug.BuildInfo{}, true, ""},
+		{"no build info", nil, false, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			readBuildInfo = func() (*debug.BuildInfo, bool) { return tt.info, tt.ok }
+			l := newTestLogger(io.Discard, WithBuildInfo())
+			entries := l.Capture(func() { l.Info("started") })
+
+			commit, ok := entries[0].Details["commit"]
+			if commit != tt.commit || ok != (tt.commit != "") {
+				t.Errorf("commit = %q (present %t), want %q", commit, ok, tt.commit)
+			}
+		})
+	}
+}
+
+func TestHostMetadata(t *testing.T) {
+	l := newTestLogger(io.Discard, WithHostMetadata())
+	entries := l.Capture(func() {
+		l.Info("first")
+		l.Info("second", "user", "alice")
+	})
+
+	want := map[string]string{"pid": strconv.Itoa(os.Getpid()), "os": runtime.GOOS}
+	if hostname, err := os.Hostname(); err == nil {
+		want["hostname"] = hostname
+	}
+	for _, e := range entries {
+		got := map[string]string{}
+		for k := range want {
+			if v, ok := e.Details[k]; ok {
+				got[k] = v
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q has host metadata %v, want %v", e.Message, got, want)
+		}
+	}
+}
+
+func TestSeverityHooks(t *testing.T) {
+	l := newTestLogger(io.Discard,
+		WithSeverityHook(logging.Warning, func(details map[string]string) { details["dashboard"] = "https://example.com/d" }),
+		WithSeverityHook(logging.Error, func(details map[string]string) { details["runbook_url"] = "https://example.com/rb" }),
+	)
+	entries := l.Capture(func() {
+		l.Info("info")
+		l.Warn("warning")
+		l.Error("error")
+		l.Logf(logging.Critical, "critical")
+	})
+
+	for i, want := range []map[string]string{
+		{},
+		{"dashboard": "https://example.com/d"},
+		{"dashboard": "https://example.com/d", "runbook_url": "https://example.com/rb"},
+		{"dashboard": "https://example.com/d", "runbook_url": "https://example.com/rb"},
+	} {
+		if got := entries[i].Details; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s details = %v, want %v", entries[i].Message, got, want)
+		}
+	}
+}
+
+func TestSchemaVersion(t *testing.T) {
+	var out bytes.Buffer
+	l := newTestLogger(&out, WithSchemaVersion("2"))
+	entries := l.Capture(func() {
+		l.Default("default")
+		l.Debug("debug")
+		l.Info("info")
+		l.Warn("warning")
+		l.Error("error", "schema_version", "1")
+		l.Logf(logging.Emergency, "emergency")
+	})
+	for _, e := range entries {
+		if got := e.Details["schema_version"]; got != "2" {
+			t.Errorf("%s: schema_version = %q, want 2", e.Message, got)
+		}
+	}
+
+	l.Info("backup")
+	if got, want := out.String(), "Info      : map[msg:backup schema_version:2]\n"; got != want {
+		t.Errorf("backup = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+package cloudlogging
+
+import (
+	"strconv"
+	"sync"
+
+	"cloud.google.com/go/logging"
+)
+
+const defaultPauseBufferSize = 1000
+
+type pause struct {
+	mu      sync.Mutex
+	paused  bool
+	held    []logging.Entry
+	dropped int
+}
+
+// WithPauseBufferSize sets how many entries are held for Cloud Logging while
+// the logger is paused. It defaults to 1000.
+func WithPauseBufferSize(n int) Option {
+	return func(l *Logger) {
+		l.pauseBufferSize = n
+	}
+}
+
+// Pause stops sending entries to Cloud Logging, for example during a
+// maintenance window. Meanwhile entries are written to the backup logger and
+// held in memory, up to WithPauseBufferSize entries, to be sent by Resume.
+// Pause has no effect on the stdout and logfmt outputs.
+func (l *Logger) Pause() {
+	l.pause.mu.Lock()
+	l.pause.paused = true
+	l.pause.mu.Unlock()
+}
+
+// Resume sends the entries held since Pause, with their original timestamps,
+// and resumes sending entries to Cloud Logging. If entries were dropped
+// because the buffer was full, a Warning entry reports how many.
+func (l *Logger) Resume() {
+	p := &l.pause
+	p.mu.Lock()
+	held, dropped := p.held, p.dropped
+	p.paused, p.held, p.dropped = false, nil, 0
+	p.mu.Unlock()
+
+	// Held entries are already in the backup output, so they are not
+	// replayed once the client is no longer usable.
+	if l.conn != nil && !isDone(l.systemCtx) {
+		for _, entry := range held {
+			l.send(entry)
+		}
+	}
+	if dropped > 0 {
+		l.logData(logging.Warning, payload("entries dropped while paused", "count", strconv.Itoa(dropped)))
+	}
+}
+
+// IsPaused reports whether the logger is paused.
+func (l *Logger) IsPaused() bool {
+	l.pause.mu.Lock()
+	defer l.pause.mu.Unlock()
+	return l.pause.paused
+}
+
+// hold keeps entry for Resume and reports whether the logger is paused.
+func (l *Logger) hold(entry logging.Entry) bool {
+	p := &l.pause
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.paused {
+		return false
+	}
+	if len(p.held) >= l.pauseBufferSize {
+		p.dropped++
+		return true
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = l.now()
+	}
+	// The payload may be reused once write returns, as under
+	// WithMinimalMode, so a copy is held.
+	if data, ok := entry.Payload.(map[string]string); ok {
+		entry.Payload = copyMap(data)
+	}
+	p.held = append(p.held, entry)
+	return true
+}
@@ -0,0 +1,54 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestPauseResume(t *testing.T) {
+	var backup bytes.Buffer
+	srv := &fakeServer{}
+	l := newServedLogger(t, srv, &backup, WithPauseBufferSize(2))
+	defer l.Close()
+
+	l.Pause()
+	if !l.IsPaused() {
+		t.Fatal("IsPaused() = false after Pause")
+	}
+	for _, msg := range []string{"one", "two", "three"} {
+		l.Info(msg)
+	}
+	if err := l.FlushCtx(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.total(); got != 0 {
+		t.Errorf("server received %d entries while paused, want 0", got)
+	}
+	if got := strings.Count(backup.String(), "\n"); got != 3 {
+		t.Errorf("backup has %d lines while paused, want 3:\n%s", got, backup.String())
+	}
+
+	l.Resume()
+	if l.IsPaused() {
+		t.Error("IsPaused() = true after Resume")
+	}
+	l.Info("four")
+	if err := l.FlushCtx(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, entry := range srv.received() {
+		fields := entry.GetJsonPayload().GetFields()
+		got = append(got, fields["msg"].GetStringValue()+fields["count"].GetStringValue())
+	}
+	sort.Strings(got)
+	want := []string{"entries dropped while paused1", "four", "one", "two"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("server received %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package cloudlogging
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CheckPermissions writes a small Debug entry synchronously to verify that the
+// credentials may write log entries, so that a misconfigured deployment can
+// fail at startup instead of losing entries later. Each call uses a little
+// write quota. It returns nil when the logger has no client.
+func (l *Logger) CheckPermissions(ctx context.Context) error {
+	if l.conn == nil {
+		return nil
+	}
+
+	entry := logging.Entry{
+		Payload:  map[string]string{"msg": "cloudlogging permission check"},
+		Severity: logging.Debug,
+	}
+	err := l.logSync(ctx, l.clientLogger(), entry)
+	if err != nil {
+		l.recordError(err, entry.Severity)
+	}
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.PermissionDenied:
+		return fmt.Errorf("cloudlogging: missing permission logging.logEntries.create: %w", err)
+	case codes.Unauthenticated:
+		return fmt.Errorf("cloudlogging: invalid or missing credentials: %w", err)
+	default:
+		return fmt.Errorf("cloudlogging: permission check failed: %w", err)
+	}
+}
@@ -0,0 +1,45 @@
+package cloudlogging
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		fail error
+		want string
+	}{
+		{"allowed", nil, ""},
+		{"denied", status.Error(codes.PermissionDenied, "denied"), "missing permission logging.logEntries.create"},
+		{"unauthenticated", status.Error(codes.Unauthenticated, "no token"), "invalid or missing credentials"},
+		{"invalid", status.Error(codes.InvalidArgument, "bad entry"), "permission check failed"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &fakeServer{fail: tt.fail}
+			l := newServedLogger(t, srv, io.Discard)
+			defer l.Close()
+
+			err := l.CheckPermissions(context.Background())
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("CheckPermissions() = %v, want nil", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("CheckPermissions() = %v, want an error containing %q", err, tt.want)
+			}
+			if tt.want == "" && srv.total() != 1 {
+				t.Errorf("server received %d entries, want the check entry", srv.total())
+			}
+		})
+	}
+
+	if err := newTestLogger(io.Discard).CheckPermissions(context.Background()); err != nil {
+		t.Errorf("CheckPermissions() without client = %v, want nil", err)
+	}
+}
@@ -0,0 +1,46 @@
+package cloudlogging
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"cloud.google.com/go/logging"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// protoMessageLength bounds the "msg" label of LogProto entries, since labels
+// are indexed and meant to be short.
+const protoMessageLength = 256
+
+// LogProto logs m as the entry's proto payload. Because a proto payload has
+// no room for extra fields, msg is attached as the "msg" entry label, with
+// invalid UTF-8 replaced as for payloads and cut to 256 bytes. Keep it short
+// and free of request data. On the backup path m is rendered with prototext
+// and msg is kept whole.
+func (l *Logger) LogProto(ctx context.Context, severity logging.Severity, msg string, m proto.Message) error {
+	p, err := anypb.New(m)
+	if err != nil {
+		return err
+	}
+	entry := logging.Entry{
+		Payload:  p,
+		Severity: severity,
+		Labels:   map[string]string{"msg": l.protoMessageLabel(msg)},
+	}
+	l.applyContext(ctx, &entry)
+	l.write(entry, payload(msg, "proto", prototext.Format(m)))
+	return nil
+}
+
+func (l *Logger) protoMessageLabel(msg string) string {
+	if l.sanitizeUTF8 && !utf8.ValidString(msg) {
+		msg = strings.ToValidUTF8(msg, string(utf8.RuneError))
+	}
+	if len(msg) > protoMessageLength {
+		msg = truncate(msg, protoMessageLength)
+	}
+	return msg
+}
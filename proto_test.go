@@ -0,0 +1,32 @@
+package cloudlogging
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestProtoMessageLabel(t *testing.T) {
+	l := newTestLogger(io.Discard)
+	for _, tt := range []struct {
+		name, msg string
+	}{
+		{"short", "order placed"},
+		{"invalid UTF-8", "bad \xff byte"},
+		{"long", strings.Repeat("é", 300)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := l.protoMessageLabel(tt.msg)
+			if !utf8.ValidString(got) {
+				t.Errorf("label %q is not valid UTF-8", got)
+			}
+			if len(got) > protoMessageLength {
+				t.Errorf("label is %d bytes, want at most %d", len(got), protoMessageLength)
+			}
+			if len(tt.msg) <= protoMessageLength && utf8.ValidString(tt.msg) && got != tt.msg {
+				t.Errorf("label = %q, want %q unchanged", got, tt.msg)
+			}
+		})
+	}
+}
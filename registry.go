@@ -0,0 +1,56 @@
+package cloudlogging
+
+import (
+	"log"
+	"os"
+	"sync"
+)
+
+var registry struct {
+	mu   sync.Mutex
+	live int
+}
+
+// WithRegistryTracking counts the logger in a package-level registry until it
+// is closed, and warns when more than threshold tracked loggers are alive at
+// once, which usually means NewLogger is called per request.
+func WithRegistryTracking(threshold int) Option {
+	return func(l *Logger) {
+		l.trackThreshold = threshold
+		l.tracked = true
+	}
+}
+
+// LiveLoggerCount returns the number of tracked loggers that are not closed.
+func LiveLoggerCount() int {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	return registry.live
+}
+
+func (l *Logger) track() {
+	if !l.tracked {
+		return
+	}
+	registry.mu.Lock()
+	registry.live++
+	live := registry.live
+	registry.mu.Unlock()
+
+	if live > l.trackThreshold {
+		warn := l.backup
+		if warn == nil {
+			warn = log.New(os.Stderr, "", log.LstdFlags)
+		}
+		warn.Printf("cloudlogging: %d live loggers exceed the threshold of %d, loggers are probably leaking", live, l.trackThreshold)
+	}
+}
+
+func (l *Logger) untrack() {
+	if !l.tracked {
+		return
+	}
+	registry.mu.Lock()
+	registry.live--
+	registry.mu.Unlock()
+}
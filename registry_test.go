@@ -0,0 +1,38 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestRegistryTracking(t *testing.T) {
+	base := LiveLoggerCount()
+	var out bytes.Buffer
+	var loggers []*Logger
+	for i := 0; i < 2; i++ {
+		l, err := New(context.Background(), "test-project", "test", log.New(&out, "", 0),
+			WithLogfmtSink(io.Discard), WithRegistryTracking(base+1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		loggers = append(loggers, l)
+	}
+
+	if got := LiveLoggerCount(); got != base+2 {
+		t.Errorf("LiveLoggerCount() = %d, want %d", got, base+2)
+	}
+	if got := strings.Count(out.String(), "loggers are probably leaking"); got != 1 {
+		t.Errorf("warned %d times, want once, when the threshold was exceeded:\n%s", got, out.String())
+	}
+
+	for _, l := range loggers {
+		l.Close()
+	}
+	if got := LiveLoggerCount(); got != base {
+		t.Errorf("LiveLoggerCount() after Close = %d, want %d", got, base)
+	}
+}
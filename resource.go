@@ -0,0 +1,51 @@
+package cloudlogging
+
+import (
+	"cloud.google.com/go/logging"
+	mrpb "google.golang.org/genproto/googleapis/api/monitoredres"
+)
+
+// WithPromoteLabels writes entries against resource, with the common labels
+// named by keys moved to the resource's labels.
+//
+// Entry labels are stored on every entry and describe the entry itself.
+// Resource labels describe where the entries come from, are stored once per
+// request, and are what the console groups logs by. Labels that are constant
+// for the life of the process are better as resource labels.
+//
+// resource replaces the resource the client detects, such as the Cloud Run
+// revision or GKE container, and its project_id label, so it must be complete:
+// its type and every label that type requires. The keys must be labels the
+// type defines. A nil resource leaves the detected resource and the labels
+// alone.
+func WithPromoteLabels(resource *mrpb.MonitoredResource, keys ...string) Option {
+	return func(l *Logger) {
+		l.resource = resource
+		l.promoteLabels = append(l.promoteLabels, keys...)
+	}
+}
+
+// loggerOptions returns the options for the client loggers of l. Promoted
+// labels are moved from the common labels to the resource's.
+func (l *Logger) loggerOptions() []logging.LoggerOption {
+	labels := copyMap(l.commonLabels)
+	var opts []logging.LoggerOption
+	if l.resource != nil {
+		resourceLabels := copyMap(l.resource.Labels)
+		for _, key := range l.promoteLabels {
+			if value, ok := labels[key]; ok {
+				resourceLabels[key] = value
+				delete(labels, key)
+			}
+		}
+		opts = append(opts, logging.CommonResource(&mrpb.MonitoredResource{
+			Type:   l.resource.Type,
+			Labels: resourceLabels,
+		}))
+	}
+	opts = append(opts, logging.CommonLabels(labels))
+	if l.flushBufferSize > 0 {
+		opts = append(opts, logging.EntryCountThreshold(l.flushBufferSize))
+	}
+	return opts
+}
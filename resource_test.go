@@ -0,0 +1,43 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	mrpb "google.golang.org/genproto/googleapis/api/monitoredres"
+)
+
+func TestPromoteLabels(t *testing.T) {
+	srv := &fakeServer{}
+	resource := &mrpb.MonitoredResource{
+		Type:   "generic_task",
+		Labels: map[string]string{"project_id": "test-project", "location": "europe-west1", "namespace": "billing", "job": "worker"},
+	}
+	l := newServedLogger(t, srv, io.Discard,
+		WithLabels("task_id", "worker-7", "team", "payments"),
+		WithPromoteLabels(resource, "task_id"))
+	l.Info("started")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := srv.received()
+	if len(entries) != 1 {
+		t.Fatalf("server received %d entries, want 1", len(entries))
+	}
+	entry := entries[0]
+	if got := entry.GetResource().GetType(); got != "generic_task" {
+		t.Errorf("resource type = %q, want generic_task", got)
+	}
+	wantResource := map[string]string{"project_id": "test-project", "location": "europe-west1", "namespace": "billing", "job": "worker", "task_id": "worker-7"}
+	if got := entry.GetResource().GetLabels(); !reflect.DeepEqual(got, wantResource) {
+		t.Errorf("resource labels = %v, want %v", got, wantResource)
+	}
+	if want := map[string]string{"team": "payments"}; !reflect.DeepEqual(entry.Labels, want) {
+		t.Errorf("entry labels = %v, want %v", entry.Labels, want)
+	}
+	if _, ok := resource.Labels["task_id"]; ok {
+		t.Error("the caller's resource was modified")
+	}
+}
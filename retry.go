@@ -0,0 +1,28 @@
+package cloudlogging
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// LogRetryAttempt logs one attempt of a retried operation with the standard
+// "op", "attempt", "delay_ms" and "error" details, so retry logs can be queried
+// the same way across services. delay is the wait before the next attempt; err
+// is the error of this attempt and may be nil.
+func (l *Logger) LogRetryAttempt(ctx context.Context, severity logging.Severity, op string, attempt int, delay time.Duration, err error) {
+	if !l.enabled(severity) {
+		return
+	}
+	data := payload("retrying "+op,
+		"op", op,
+		"attempt", strconv.Itoa(attempt),
+		"delay_ms", milliseconds(delay),
+	)
+	if err != nil {
+		data["error"] = err.Error()
+	}
+	l.logContext(ctx, severity, data)
+}
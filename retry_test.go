@@ -0,0 +1,41 @@
+package cloudlogging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestLogRetryAttempt(t *testing.T) {
+	l := newTestLogger(io.Discard, WithMinSeverity(logging.Info))
+	entries := l.Capture(func() {
+		l.LogRetryAttempt(context.Background(), logging.Warning, "charge", 2, 1500*time.Millisecond, errors.New("unavailable"))
+		l.LogRetryAttempt(context.Background(), logging.Info, "charge", 3, 0, nil)
+		l.LogRetryAttempt(context.Background(), logging.Debug, "charge", 4, time.Second, nil)
+	})
+
+	want := []map[string]string{
+		{"op": "charge", "attempt": "2", "delay_ms": "1500", "error": "unavailable"},
+		{"op": "charge", "attempt": "3", "delay_ms": "0"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: the Debug attempt is filtered", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if e.Message != "retrying charge" || !reflect.DeepEqual(e.Details, want[i]) {
+			t.Errorf("entry %d = %q %v, want %q %v", i, e.Message, e.Details, "retrying charge", want[i])
+		}
+		if _, err := strconv.Atoi(e.Details["attempt"]); err != nil {
+			t.Errorf("attempt %q is not an integer", e.Details["attempt"])
+		}
+		if _, err := strconv.ParseFloat(e.Details["delay_ms"], 64); err != nil {
+			t.Errorf("delay_ms %q is not a number", e.Details["delay_ms"])
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package cloudlogging
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+// rotatingFile is an io.Writer appending to a file that is rotated once it
+// would grow past maxSize bytes, keeping at most maxBackups old files named
+// path.1 (newest) to path.N.
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// WithFileBackup writes backup output to the file at path, rotating it when
+// it reaches maxSizeMB megabytes and keeping maxBackups rotated files, so the
+// fallback logs cannot fill the disk. A maxSizeMB of zero or less means no
+// size limit. The backup logger's prefix and flags are kept. The file is
+// closed by Close.
+func WithFileBackup(path string, maxSizeMB int, maxBackups int) Option {
+	return func(l *Logger) {
+		prefix, flags := "", log.LstdFlags
+		if l.backup != nil {
+			prefix, flags = l.backup.Prefix(), l.backup.Flags()
+		}
+		l.fileBackup = &rotatingFile{
+			path:       path,
+			maxSize:    int64(maxSizeMB) * 1024 * 1024, // zero or less: no limit
+			maxBackups: maxBackups,
+		}
+		l.backup = log.New(l.fileBackup, prefix, flags)
+	}
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	if r.maxBackups <= 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return r.open()
+	}
+	os.Remove(r.backupName(r.maxBackups))
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(r.backupName(i), r.backupName(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(r.path, r.backupName(1)); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) backupName(i int) string {
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,58 @@
+package cloudlogging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	line := strings.Repeat("x", 9) + "\n"
+	for _, tt := range []struct {
+		name       string
+		maxSize    int64
+		maxBackups int
+		writes     int
+		wantFiles  []string
+		wantSize   int64 // of the current file
+	}{
+		{"under limit", 100, 2, 5, []string{"backup.log"}, 50},
+		{"rotates past limit", 25, 2, 3, []string{"backup.log", "backup.log.1"}, 10},
+		{"keeps max backups", 10, 2, 4, []string{"backup.log", "backup.log.1", "backup.log.2"}, 10},
+		{"no backups", 10, 0, 3, []string{"backup.log"}, 10},
+		{"no limit", 0, 2, 5, []string{"backup.log"}, 50},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			r := &rotatingFile{path: filepath.Join(dir, "backup.log"), maxSize: tt.maxSize, maxBackups: tt.maxBackups}
+			for i := 0; i < tt.writes; i++ {
+				if _, err := r.Write([]byte(line)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := r.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Name())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantFiles, ",") {
+				t.Errorf("files = %v, want %v", got, tt.wantFiles)
+			}
+			info, err := os.Stat(r.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size() != tt.wantSize {
+				t.Errorf("current file size = %d, want %d", info.Size(), tt.wantSize)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package cloudlogging
+
+import (
+	"runtime"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// WithRuntimeStats logs a "runtime stats" entry at Info every interval with the
+// heap in use, the number of completed GC cycles and the number of
+// goroutines, to correlate memory growth with other entries. Reading the
+// stats briefly stops the world, so keep interval in the order of minutes.
+// The entries stop when the logger is closed.
+func WithRuntimeStats(interval time.Duration) Option {
+	return func(l *Logger) {
+		l.runtimeStatsInterval = interval
+	}
+}
+
+func (l *Logger) startRuntimeStats() {
+	if l.runtimeStatsInterval <= 0 {
+		return
+	}
+	l.every(l.runtimeStatsInterval, l.logRuntimeStats)
+}
+
+func (l *Logger) logRuntimeStats() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	l.logData(logging.Info, payload("runtime stats",
+		"heap_alloc_bytes", strconv.FormatUint(m.HeapAlloc, 10),
+		"heap_objects", strconv.FormatUint(m.HeapObjects, 10),
+		"num_gc", strconv.FormatUint(uint64(m.NumGC), 10),
+		"goroutines", strconv.Itoa(runtime.NumGoroutine()),
+	))
+}
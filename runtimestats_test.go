@@ -0,0 +1,36 @@
+package cloudlogging
+
+import (
+	"context"
+	"io"
+	"log"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRuntimeStats(t *testing.T) {
+	l, err := New(context.Background(), "test-project", "test", log.New(io.Discard, "", 0),
+		WithLogfmtSink(io.Discard), WithRuntimeStats(5*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	entries := l.Capture(func() { time.Sleep(50 * time.Millisecond) })
+	if len(entries) == 0 {
+		t.Fatal("no runtime stats within 50ms at a 5ms interval")
+	}
+	e := entries[0]
+	if e.Message != "runtime stats" {
+		t.Errorf("message = %q, want %q", e.Message, "runtime stats")
+	}
+	for _, field := range []string{"heap_alloc_bytes", "heap_objects", "num_gc", "goroutines"} {
+		if _, err := strconv.ParseUint(e.Details[field], 10, 64); err != nil {
+			t.Errorf("%s = %q, want a number", field, e.Details[field])
+		}
+	}
+	if n, _ := strconv.Atoi(e.Details["goroutines"]); n < 1 {
+		t.Errorf("goroutines = %d, want at least this one", n)
+	}
+}
@@ -0,0 +1,41 @@
+package cloudlogging
+
+import "cloud.google.com/go/logging"
+
+// The Cloud Logging severities, so callers of Logf and the other methods
+// taking a severity need not import cloud.google.com/go/logging.
+const (
+	SeverityDefault   = logging.Default
+	SeverityDebug     = logging.Debug
+	SeverityInfo      = logging.Info
+	SeverityNotice    = logging.Notice
+	SeverityWarning   = logging.Warning
+	SeverityError     = logging.Error
+	SeverityCritical  = logging.Critical
+	SeverityAlert     = logging.Alert
+	SeverityEmergency = logging.Emergency
+)
+
+// WithDefaultSeverity sets the severity that replaces severities Cloud Logging
+// does not define, such as logging.Severity(250). It defaults to
+// logging.Default.
+func WithDefaultSeverity(severity logging.Severity) Option {
+	return func(l *Logger) {
+		l.defaultSeverity = severity
+	}
+}
+
+// normalizeSeverity clamps severities below Default or above Emergency to
+// those bounds and replaces other undefined severities with the default one.
+func (l *Logger) normalizeSeverity(severity logging.Severity) logging.Severity {
+	switch {
+	case severity < logging.Default:
+		return logging.Default
+	case severity > logging.Emergency:
+		return logging.Emergency
+	case severity%100 != 0:
+		return l.defaultSeverity
+	default:
+		return severity
+	}
+}
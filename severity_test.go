@@ -0,0 +1,144 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestNormalizeSeverity(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		fallback logging.Severity
+		in, want logging.Severity
+	}{
+		{"defined", logging.Default, logging.Warning, logging.Warning},
+		{"default", logging.Default, logging.Default, logging.Default},
+		{"emergency", logging.Default, logging.Emergency, logging.Emergency},
+		{"below default", logging.Default, -100, logging.Default},
+		{"above emergency", logging.Default, 900, logging.Emergency},
+		{"undefined", logging.Default, 250, logging.Default},
+		{"undefined with fallback", logging.Notice, 250, logging.Notice},
+		{"out of range with fallback", logging.Notice, 1000, logging.Emergency},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLogger(io.Discard, WithDefaultSeverity(tt.fallback))
+			if got := l.normalizeSeverity(tt.in); got != tt.want {
+				t.Errorf("normalizeSeverity(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogfClampsSeverity(t *testing.T) {
+	l := newTestLogger(io.Discard)
+	entries := l.Capture(func() {
+		l.Logf(-100, "below %s", "default")
+		l.Logf(1000, "above %s", "emergency")
+		l.log(-5, "negative")
+	})
+	want := []logging.Severity{logging.Default, logging.Emergency, logging.Default}
+	if len(entries) != len(want) {
+		t.Fatalf("captured %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if e.Severity != want[i] {
+			t.Errorf("entry %q has severity %v, want %v", e.Message, e.Severity, want[i])
+		}
+	}
+}
+
+func TestDefaultSeverity(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"no minimum", nil, "Default   : map[msg:unassigned]\n"},
+		{"minimum Default", []Option{WithMinSeverity(logging.Default)}, "Default   : map[msg:unassigned]\n"},
+		{"minimum Info", []Option{WithMinSeverity(logging.Info)}, ""},
+		{"minimum Debug", []Option{WithMinSeverity(logging.Debug)}, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var backup bytes.Buffer
+			l := newTestLogger(&backup, tt.opts...)
+			l.Default("unassigned")
+			if got := backup.String(); got != tt.want {
+				t.Errorf("backup = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultThroughBackupOnly(t *testing.T) {
+	var backup bytes.Buffer
+	l := AsBackupOnly(log.New(&backup, "", 0))
+	l.Default("unassigned")
+	l.Info("assigned")
+	want := "Default   : map[msg:unassigned]\nInfo      : map[msg:assigned]\n"
+	if got := backup.String(); got != want {
+		t.Errorf("backup = %q, want %q", got, want)
+	}
+}
+
+func TestSeverityComparator(t *testing.T) {
+	// Notice ranks between Warning and Error.
+	rank := func(s logging.Severity) int {
+		if s == logging.Notice {
+			return int(logging.Warning) + 50
+		}
+		return int(s)
+	}
+	compare := func(a, b logging.Severity) int { return rank(a) - rank(b) }
+
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"numeric", []Option{WithMinSeverity(logging.Warning)}, []string{"warning", "error"}},
+		{"custom", []Option{WithMinSeverity(logging.Warning), WithSeverityComparator(compare)}, []string{"notice", "warning", "error"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLogger(io.Discard, tt.opts...)
+			entries := l.Capture(func() {
+				l.Info("info")
+				l.Logf(logging.Notice, "notice")
+				l.Warn("warning")
+				l.Error("error")
+			})
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("logged %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverityConstants(t *testing.T) {
+	for _, tt := range []struct {
+		got, want logging.Severity
+		value     int
+	}{
+		{SeverityDefault, logging.Default, 0},
+		{SeverityDebug, logging.Debug, 100},
+		{SeverityInfo, logging.Info, 200},
+		{SeverityNotice, logging.Notice, 300},
+		{SeverityWarning, logging.Warning, 400},
+		{SeverityError, logging.Error, 500},
+		{SeverityCritical, logging.Critical, 600},
+		{SeverityAlert, logging.Alert, 700},
+		{SeverityEmergency, logging.Emergency, 800},
+	} {
+		if tt.got != tt.want || int(tt.got) != tt.value {
+			t.Errorf("%v = %d, want %v (%d)", tt.got, int(tt.got), tt.want, tt.value)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+//go:build go1.21
+
+package cloudlogging
+
+import (
+	"context"
+	"log/slog"
+
+	"cloud.google.com/go/logging"
+)
+
+type slogHandler struct {
+	logger  ILogger
+	details []string
+	group   string
+}
+
+// NewSlogHandler returns a slog.Handler that logs records through logger, so
+// code using log/slog reaches Cloud Logging and the backup logger alike.
+// Levels map to severities: below Info to Debug, Info to Info, Warn to
+// Warning, Error to Error and above Error to Critical. Attributes become
+// details, with group names joined by dots. With a *Logger, the context-aware
+// path is used, so a record's context contributes its trace and labels, and
+// the record's time is the entry timestamp.
+func NewSlogHandler(logger ILogger) slog.Handler {
+	return &slogHandler{logger: logger}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if l, ok := h.logger.(*Logger); ok {
+		return l.enabled(slogSeverity(level))
+	}
+	return true
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	details := append([]string(nil), h.details...)
+	r.Attrs(func(a slog.Attr) bool {
+		details = appendSlogAttr(details, h.group, a)
+		return true
+	})
+
+	severity := slogSeverity(r.Level)
+	if l, ok := h.logger.(*Logger); ok {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		data := payload(r.Message, details...)
+		entry := l.contextEntry(ctx, severity, data)
+		entry.Timestamp = r.Time
+		l.write(entry, data)
+		return nil
+	}
+	switch severity {
+	case logging.Debug:
+		h.logger.Debug(r.Message, details...)
+	case logging.Info:
+		h.logger.Info(r.Message, details...)
+	case logging.Warning:
+		h.logger.Warn(r.Message, details...)
+	default:
+		h.logger.Error(r.Message, details...)
+	}
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	details := append([]string(nil), h.details...)
+	for _, a := range attrs {
+		details = appendSlogAttr(details, h.group, a)
+	}
+	return &slogHandler{logger: h.logger, details: details, group: h.group}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{logger: h.logger, details: h.details, group: h.group + name + "."}
+}
+
+// appendSlogAttr appends a as key/value details, flattening groups.
+func appendSlogAttr(details []string, prefix string, a slog.Attr) []string {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return details
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			details = appendSlogAttr(details, prefix, ga)
+		}
+		return details
+	}
+	return append(details, prefix+a.Key, a.Value.String())
+}
+
+func slogSeverity(level slog.Level) logging.Severity {
+	switch {
+	case level < slog.LevelInfo:
+		return logging.Debug
+	case level < slog.LevelWarn:
+		return logging.Info
+	case level < slog.LevelError:
+		return logging.Warning
+	case level == slog.LevelError:
+		return logging.Error
+	default:
+		return logging.Critical
+	}
+}
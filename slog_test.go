@@ -0,0 +1,89 @@
+//go:build go1.21
+
+package cloudlogging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestSlogHandler(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name     string
+		handler  func(slog.Handler) slog.Handler
+		level    slog.Level
+		attrs    []slog.Attr
+		severity logging.Severity
+		details  map[string]string
+	}{
+		{"debug below", nil, slog.LevelDebug - 4, nil, logging.Debug, map[string]string{}},
+		{"debug", nil, slog.LevelDebug, nil, logging.Debug, map[string]string{}},
+		{"info", nil, slog.LevelInfo, nil, logging.Info, map[string]string{}},
+		{"warn", nil, slog.LevelWarn, nil, logging.Warning, map[string]string{}},
+		{"error", nil, slog.LevelError, nil, logging.Error, map[string]string{}},
+		{"above error", nil, slog.LevelError + 4, nil, logging.Critical, map[string]string{}},
+		{
+			"values", nil, slog.LevelInfo,
+			[]slog.Attr{slog.Int("status", 200), slog.Duration("took", 1500*time.Millisecond), slog.Bool("cached", true)},
+			logging.Info, map[string]string{"status": "200", "took": "1.5s", "cached": "true"},
+		},
+		{
+			"group attr", nil, slog.LevelInfo,
+			[]slog.Attr{slog.Group("req", slog.String("method", "GET"), slog.Group("url", slog.String("path", "/pay")))},
+			logging.Info, map[string]string{"req.method": "GET", "req.url.path": "/pay"},
+		},
+		{
+			"with attrs and groups",
+			func(h slog.Handler) slog.Handler {
+				h = h.WithAttrs([]slog.Attr{slog.String("service", "billing")})
+				h = h.WithGroup("")
+				h = h.WithGroup("http").WithAttrs([]slog.Attr{slog.String("method", "POST")})
+				return h.WithGroup("resp")
+			},
+			slog.LevelWarn,
+			[]slog.Attr{slog.Int("status", 503)},
+			logging.Warning, map[string]string{"service": "billing", "http.method": "POST", "http.resp.status": "503"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLogger(io.Discard)
+			h := NewSlogHandler(l)
+			if tt.handler != nil {
+				h = tt.handler(h)
+			}
+			r := slog.NewRecord(at, tt.level, "request served", 0)
+			r.AddAttrs(tt.attrs...)
+
+			entries := l.Capture(func() {
+				if err := h.Handle(context.Background(), r); err != nil {
+					t.Fatal(err)
+				}
+			})
+			want := []Entry{{Severity: tt.severity, Message: "request served", Details: tt.details, Labels: map[string]string{}, Timestamp: at}}
+			if !reflect.DeepEqual(entries, want) {
+				t.Errorf("entries = %+v\nwant %+v", entries, want)
+			}
+		})
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	h := NewSlogHandler(newTestLogger(io.Discard, WithMinSeverity(logging.Warning)))
+	for level, want := range map[slog.Level]bool{
+		slog.LevelDebug: false,
+		slog.LevelInfo:  false,
+		slog.LevelWarn:  true,
+		slog.LevelError: true,
+	} {
+		if got := h.Enabled(context.Background(), level); got != want {
+			t.Errorf("Enabled(%v) = %t, want %t", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package cloudlogging
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/logging"
+)
+
+// Span records the start of the operation name and returns a function that
+// logs an Info entry with "operation" and "duration_ms" when called:
+//
+//	defer l.Span(ctx, "sync-users")()
+//
+// When deferred directly like this and the operation panics, the entry is
+// logged at Error with error=true and the panic continues.
+func (l *Logger) Span(ctx context.Context, name string) func(details ...string) {
+	start := l.now()
+	return func(details ...string) {
+		r := recover()
+
+		data := payload(name, details...)
+		data["operation"] = name
+		data["duration_ms"] = milliseconds(l.now().Sub(start))
+		severity := logging.Info
+		if r != nil {
+			severity = logging.Error
+			data["error"] = "true"
+			data["panic"] = fmt.Sprint(r)
+		}
+		l.logContext(ctx, severity, data)
+
+		if r != nil {
+			panic(r)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cloudlogging
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+type spikeAlert struct {
+	mu      sync.Mutex
+	start   time.Time
+	count   int
+	tripped bool
+}
+
+// WithSpikeAlert logs a single Warning and calls notify once per window when
+// more than limit entries are logged within that window. Entries are never
+// dropped by the alert.
+func WithSpikeAlert(limit int, window time.Duration, notify func(count int)) Option {
+	return func(l *Logger) {
+		l.spikeLimit = limit
+		l.spikeWindow = window
+		l.spikeNotify = notify
+	}
+}
+
+func (l *Logger) checkSpike() {
+	s := &l.spike
+	if l.spikeLimit <= 0 {
+		return
+	}
+
+	now := l.now()
+	s.mu.Lock()
+	if now.Sub(s.start) >= l.spikeWindow {
+		s.start = now
+		s.count = 0
+		s.tripped = false
+	}
+	s.count++
+	trip := s.count > l.spikeLimit && !s.tripped
+	if trip {
+		s.tripped = true
+	}
+	count := s.count
+	s.mu.Unlock()
+
+	if !trip {
+		return
+	}
+	data := payload("log spike detected", "count", strconv.Itoa(count), "window", l.spikeWindow.String())
+	l.deliver(logging.Entry{Payload: data, Severity: logging.Warning}, data)
+	if l.spikeNotify != nil {
+		l.spikeNotify(count)
+	}
+}
@@ -0,0 +1,72 @@
+package cloudlogging
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"cloud.google.com/go/logging"
+)
+
+const packagePath = "github.com/newjar/cloud-logging."
+
+// WithStackTrace attaches the caller's stack as a "stack_trace" field to
+// entries at Error and above, formatted like a Go panic so Error Reporting
+// can group them.
+func WithStackTrace() Option {
+	return func(l *Logger) {
+		l.stackFormatter = formatStack
+	}
+}
+
+// WithStackFormatter is like WithStackTrace but formats the stack with format,
+// for when the default output is not grouped as wanted by Error Reporting.
+func WithStackFormatter(format func(pc []uintptr) string) Option {
+	return func(l *Logger) {
+		l.stackFormatter = format
+	}
+}
+
+func (l *Logger) addStack(severity logging.Severity, data map[string]string) {
+	if l.stackFormatter == nil || !l.atLeast(severity, logging.Error) {
+		return
+	}
+	data["stack_trace"] = l.stackFormatter(callers())
+}
+
+// callers returns the program counters of the calling goroutine, starting at
+// the first frame outside this package.
+func callers() []uintptr {
+	pc := make([]uintptr, 64)
+	pc = pc[:runtime.Callers(2, pc)]
+	for len(pc) > 0 {
+		fn := runtime.FuncForPC(pc[0] - 1)
+		if fn == nil || !strings.HasPrefix(fn.Name(), packagePath) {
+			break
+		}
+		pc = pc[1:]
+	}
+	return pc
+}
+
+// formatStack must run on the logging goroutine, whose ID it reports in the
+// header.
+func formatStack(pc []uintptr) string {
+	id := goroutineID()
+	if id == "" {
+		id = "0"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "goroutine %s [running]:\n", id)
+	frames := runtime.CallersFrames(pc)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			fmt.Fprintf(&b, "%s(...)\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package cloudlogging
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestFormatStackHeader(t *testing.T) {
+	var id, stack string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		id, stack = goroutineID(), formatStack(callers())
+	}()
+	<-done
+	header := strings.SplitN(stack, "\n", 2)[0]
+	if want := "goroutine " + id + " [running]:"; id == "" || header != want {
+		t.Errorf("stack header = %q, want %q", header, want)
+	}
+}
+
+func TestStackFormatterOnErrorOnly(t *testing.T) {
+	l := newTestLogger(io.Discard, WithStackFormatter(func(pc []uintptr) string {
+		return "formatted stack"
+	}))
+	entries := l.Capture(func() {
+		l.Info("info")
+		l.Warn("warn")
+		l.Error("error")
+		l.Logf(logging.Critical, "critical")
+	})
+	want := map[string]bool{"info": false, "warn": false, "error": true, "critical": true}
+	if len(entries) != len(want) {
+		t.Fatalf("captured %d entries, want %d", len(entries), len(want))
+	}
+	for _, e := range entries {
+		stack, ok := e.Details["stack_trace"]
+		if ok != want[e.Message] {
+			t.Errorf("%s entry has stack_trace %v, want %v", e.Message, ok, want[e.Message])
+		}
+		if ok && stack != "formatted stack" {
+			t.Errorf("%s entry stack_trace = %q, want the formatter output", e.Message, stack)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package cloudlogging
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// flushLatencyWeight is the weight of the newest sample in FlushLatency.
+const flushLatencyWeight = 0.2
+
+// Stats reports counters about the logger.
+type Stats struct {
+	// Flushes is the number of flushes and synchronous writes timed by
+	// WithDeliveryMetrics.
+	Flushes int64
+	// FlushLatency is the exponential moving average of their durations.
+	FlushLatency time.Duration
+	// LastFlushLatency is the duration of the most recent one.
+	LastFlushLatency time.Duration
+	// Entries is the number of entries delivered, to Cloud Logging or to a
+	// fallback output.
+	Entries int64
+	// EmptyMessagesDropped is the number of entries dropped by
+	// WithRequireMessage.
+	EmptyMessagesDropped int64
+	// OverlongJSONLinesDropped is the number of lines dropped by
+	// JSONLineWriter for growing past 256 KiB without a newline.
+	OverlongJSONLinesDropped int64
+}
+
+type stats struct {
+	mu sync.Mutex
+	Stats
+}
+
+// WithDeliveryMetrics times every flush of the client and every synchronous
+// write, such as those of CheckPermissions and WithCloseMessage, and reports
+// the durations in Stats.
+func WithDeliveryMetrics() Option {
+	return func(l *Logger) {
+		l.deliveryMetrics = true
+	}
+}
+
+// Stats returns a snapshot of the logger's counters.
+func (l *Logger) Stats() Stats {
+	l.stats.mu.Lock()
+	defer l.stats.mu.Unlock()
+	s := l.stats.Stats
+	s.Entries = atomic.LoadInt64(&l.entries)
+	return s
+}
+
+// flush flushes logger, timing it when delivery metrics are enabled.
+func (l *Logger) flush(logger *logging.Logger) error {
+	if !l.deliveryMetrics {
+		return logger.Flush()
+	}
+
+	start := l.now()
+	err := logger.Flush()
+	l.recordFlush(l.now().Sub(start))
+	return err
+}
+
+// logSync writes entry synchronously through logger, timing it like flush.
+func (l *Logger) logSync(ctx context.Context, logger *logging.Logger, entry logging.Entry) error {
+	if !l.deliveryMetrics {
+		return logger.LogSync(ctx, entry)
+	}
+
+	start := l.now()
+	err := logger.LogSync(ctx, entry)
+	l.recordFlush(l.now().Sub(start))
+	return err
+}
+
+func (l *Logger) recordFlush(d time.Duration) {
+	l.stats.mu.Lock()
+	defer l.stats.mu.Unlock()
+
+	s := &l.stats.Stats
+	if s.Flushes == 0 {
+		s.FlushLatency = d
+	} else {
+		s.FlushLatency += time.Duration(flushLatencyWeight * float64(d-s.FlushLatency))
+	}
+	s.LastFlushLatency = d
+	s.Flushes++
+}
@@ -0,0 +1,28 @@
+package cloudlogging
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRecordFlushAverages(t *testing.T) {
+	l := newTestLogger(io.Discard, WithDeliveryMetrics())
+
+	l.recordFlush(100 * time.Millisecond)
+	if got := l.Stats().FlushLatency; got != 100*time.Millisecond {
+		t.Fatalf("first FlushLatency = %v, want 100ms", got)
+	}
+
+	l.recordFlush(200 * time.Millisecond)
+	s := l.Stats()
+	if s.Flushes != 2 {
+		t.Errorf("Flushes = %d, want 2", s.Flushes)
+	}
+	if s.LastFlushLatency != 200*time.Millisecond {
+		t.Errorf("LastFlushLatency = %v, want 200ms", s.LastFlushLatency)
+	}
+	if want := 120 * time.Millisecond; s.FlushLatency != want {
+		t.Errorf("FlushLatency = %v, want %v", s.FlushLatency, want)
+	}
+}
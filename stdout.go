@@ -0,0 +1,129 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// Special fields recognized by the logging agent in structured JSON lines, see
+// https://cloud.google.com/logging/docs/structured-logging#special-payload-fields
+const (
+	jsonSeverityKey     = "severity"
+	jsonMessageKey      = "message"
+	jsonTimeKey         = "time"
+	jsonLabelsKey       = "logging.googleapis.com/labels"
+	jsonTraceKey        = "logging.googleapis.com/trace"
+	jsonSpanIDKey       = "logging.googleapis.com/spanId"
+	jsonTraceSampledKey = "logging.googleapis.com/trace_sampled"
+)
+
+type jsonSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithStdoutJSON writes every entry as a single JSON line to stdout instead of
+// using the Cloud Logging client. On Cloud Run, GKE and other environments
+// running the logging agent these lines are ingested with the right severity,
+// labels and trace. No client is created in this mode.
+func WithStdoutJSON() Option {
+	return func(l *Logger) {
+		l.stdout = &jsonSink{w: os.Stdout}
+	}
+}
+
+// WithSeverityKey sets the JSON key holding the severity in stdout JSON mode,
+// for agents expecting something other than "severity", such as "level".
+func WithSeverityKey(key string) Option {
+	return func(l *Logger) {
+		l.severityKey = key
+	}
+}
+
+// WithSeverityFormat sets how the severity is rendered in stdout JSON mode.
+// By default it is the upper case severity name, e.g. "WARNING".
+func WithSeverityFormat(format func(logging.Severity) any) Option {
+	return func(l *Logger) {
+		l.severityFormat = format
+	}
+}
+
+// WithJSONEncoder replaces json.Marshal for encoding JSON lines, for example
+// with an encoder that does not escape <, > and & as the standard library
+// does. A trailing newline in the output is ignored.
+func WithJSONEncoder(encode func(any) ([]byte, error)) Option {
+	return func(l *Logger) {
+		l.jsonEncoder = encode
+	}
+}
+
+func (l *Logger) writeJSON(entry logging.Entry, backupData interface{}) {
+	line := make(map[string]interface{})
+	switch fields := backupData.(type) {
+	case map[string]string:
+		for k, v := range fields {
+			line[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range fields {
+			line[k] = v
+		}
+	case string:
+		line["msg"] = fields
+	}
+	if msg, ok := line["msg"]; ok {
+		delete(line, "msg")
+		line[jsonMessageKey] = msg
+	}
+	severityKey := jsonSeverityKey
+	if l.severityKey != "" {
+		severityKey = l.severityKey
+	}
+	if l.severityFormat != nil {
+		line[severityKey] = l.severityFormat(entry.Severity)
+	} else {
+		line[severityKey] = strings.ToUpper(entry.Severity.String())
+	}
+
+	ts := entry.Timestamp
+	if ts.IsZero() {
+		ts = l.now()
+	}
+	line[jsonTimeKey] = ts.Format(time.RFC3339Nano)
+
+	labels := make(map[string]string, len(l.commonLabels)+len(entry.Labels))
+	for k, v := range l.commonLabels {
+		labels[k] = v
+	}
+	for k, v := range entry.Labels {
+		labels[k] = v
+	}
+	if len(labels) > 0 {
+		line[jsonLabelsKey] = labels
+	}
+	if entry.Trace != "" {
+		line[jsonTraceKey] = entry.Trace
+		line[jsonSpanIDKey] = entry.SpanID
+		line[jsonTraceSampledKey] = entry.TraceSampled
+	}
+
+	encode := json.Marshal
+	if l.jsonEncoder != nil {
+		encode = l.jsonEncoder
+	}
+	b, err := encode(line)
+	if err != nil {
+		l.backup.Printf("%-10s: %v", entry.Severity.String(), backupData)
+		return
+	}
+	l.stdout.mu.Lock()
+	defer l.stdout.mu.Unlock()
+	l.stdout.w.Write(append(bytes.TrimRight(b, "\n"), '\n'))
+}
@@ -0,0 +1,111 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// TestStdoutJSONKeys pins the keys the logging agent parses; renaming any of
+// them silently breaks ingestion.
+func TestStdoutJSONKeys(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	l := newTestLogger(io.Discard, WithStdoutJSON(), WithLabels("team", "billing"))
+	l.stdout.w = &out
+	l.projectID = "my-project"
+
+	ctx := ContextWithTraceparent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	entry := l.contextEntry(ctx, logging.Warning, payload("disk almost full", "user", "alice"))
+	entry.Timestamp = at
+	l.write(entry, entry.Payload)
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+		t.Fatalf("%v: %s", err, out.String())
+	}
+	want := map[string]interface{}{
+		"severity":                             "WARNING",
+		"message":                              "disk almost full",
+		"time":                                 "2024-05-01T12:00:00Z",
+		"user":                                 "alice",
+		"logging.googleapis.com/labels":        map[string]interface{}{"team": "billing"},
+		"logging.googleapis.com/trace":         "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736",
+		"logging.googleapis.com/spanId":        "00f067aa0ba902b7",
+		"logging.googleapis.com/trace_sampled": true,
+	}
+	if !reflect.DeepEqual(line, want) {
+		t.Errorf("line = %v\nwant %v", line, want)
+	}
+}
+
+func TestSeverityKey(t *testing.T) {
+	numeric := func(s logging.Severity) any { return int(s) }
+	for _, tt := range []struct {
+		name  string
+		opts  []Option
+		key   string
+		value interface{}
+	}{
+		{"default", nil, "severity", "ERROR"},
+		{"key", []Option{WithSeverityKey("level")}, "level", "ERROR"},
+		{"format", []Option{WithSeverityFormat(numeric)}, "severity", float64(500)},
+		{"key and format", []Option{WithSeverityKey("level"), WithSeverityFormat(numeric)}, "level", float64(500)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := newTestLogger(io.Discard, append([]Option{WithStdoutJSON()}, tt.opts...)...)
+			l.stdout.w = &out
+			l.Error("payment failed")
+
+			var line map[string]interface{}
+			if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+				t.Fatalf("%v: %s", err, out.String())
+			}
+			if got := line[tt.key]; got != tt.value {
+				t.Errorf("%s = %v (%T), want %v", tt.key, got, got, tt.value)
+			}
+			if _, ok := line["severity"]; ok && tt.key != "severity" {
+				t.Errorf("severity present alongside %s: %v", tt.key, line)
+			}
+		})
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	unescaped := func(v any) ([]byte, error) {
+		var b bytes.Buffer
+		enc := json.NewEncoder(&b)
+		enc.SetEscapeHTML(false)
+		err := enc.Encode(v)
+		return b.Bytes(), err
+	}
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, `"message":"a \u003c b \u0026\u0026 c \u003e d"`},
+		{"unescaped", []Option{WithJSONEncoder(unescaped)}, `"message":"a < b && c > d"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := newTestLogger(io.Discard, append([]Option{WithStdoutJSON()}, tt.opts...)...)
+			l.stdout.w = &out
+			l.Info("a < b && c > d")
+
+			if !bytes.Contains(out.Bytes(), []byte(tt.want)) {
+				t.Errorf("line %s does not contain %s", out.String(), tt.want)
+			}
+			if n := bytes.Count(out.Bytes(), []byte("\n")); n != 1 {
+				t.Errorf("line ends with %d newlines, want 1", n)
+			}
+		})
+	}
+}
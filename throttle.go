@@ -0,0 +1,112 @@
+package cloudlogging
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// throttled is the state of one error signature under WithErrorThrottle.
+type throttled struct {
+	entry      logging.Entry
+	data       map[string]string
+	count      int
+	lastSeen   time.Time
+	lastReport time.Time
+}
+
+type throttle struct {
+	mu     sync.Mutex
+	errors map[string]*throttled
+}
+
+// WithErrorThrottle limits repeated identical errors, those at Error and above
+// with the same message and "error" detail. The first occurrence is logged in
+// full. Repeats are suppressed, and a compact "still happening" entry with the
+// count so far is logged every interval while they continue. Once a repeated
+// error has not been seen for interval, a final full entry with the total
+// count is logged along with the next entry.
+func WithErrorThrottle(interval time.Duration) Option {
+	return func(l *Logger) {
+		l.throttleInterval = interval
+	}
+}
+
+// throttleError reports whether entry is suppressed by WithErrorThrottle.
+func (l *Logger) throttleError(entry logging.Entry, backupData interface{}) bool {
+	if l.throttleInterval <= 0 {
+		return false
+	}
+	now := l.now()
+	stopped := l.sweepThrottled(now)
+	defer func() {
+		for _, t := range stopped {
+			data := copyMap(t.data)
+			data["throttle"] = "stopped"
+			data["count"] = strconv.Itoa(t.count)
+			e := t.entry
+			e.Payload = data
+			l.deliver(e, data)
+		}
+	}()
+
+	data, ok := backupData.(map[string]string)
+	if !ok || !l.atLeast(entry.Severity, logging.Error) {
+		return false
+	}
+	key := data["msg"] + "\x00" + data["error"]
+
+	t := &l.throttle
+	t.mu.Lock()
+	state, seen := t.errors[key]
+	if !seen {
+		if t.errors == nil {
+			t.errors = make(map[string]*throttled)
+		}
+		t.errors[key] = &throttled{
+			entry:      entry,
+			data:       copyMap(data),
+			count:      1,
+			lastSeen:   now,
+			lastReport: now,
+		}
+		t.mu.Unlock()
+		return false
+	}
+	state.count++
+	state.lastSeen = now
+	report := now.Sub(state.lastReport) >= l.throttleInterval
+	if report {
+		state.lastReport = now
+	}
+	count := state.count
+	t.mu.Unlock()
+
+	if report {
+		summary := payload(data["msg"], "throttle", "still happening", "count", strconv.Itoa(count))
+		e := entry
+		e.Payload = summary
+		l.deliver(e, summary)
+	}
+	return true
+}
+
+// sweepThrottled removes and returns the errors not seen for an interval.
+func (l *Logger) sweepThrottled(now time.Time) []*throttled {
+	t := &l.throttle
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var stopped []*throttled
+	for key, state := range t.errors {
+		if now.Sub(state.lastSeen) >= l.throttleInterval {
+			delete(t.errors, key)
+			if state.count > 1 {
+				stopped = append(stopped, state)
+			}
+		}
+	}
+	return stopped
+}
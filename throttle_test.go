@@ -0,0 +1,39 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestErrorThrottle(t *testing.T) {
+	clock := newFakeClock()
+	l := newTestLogger(io.Discard, WithClock(clock.Now), WithErrorThrottle(time.Minute))
+
+	entries := l.Capture(func() {
+		for _, wait := range []time.Duration{0, 10 * time.Second, 40 * time.Second, 10 * time.Second, 10 * time.Second} {
+			clock.Advance(wait)
+			l.Error("db write failed", "error", "timeout")
+		}
+		l.Error("cache miss storm")
+		clock.Advance(time.Minute)
+		l.Info("recovered")
+	})
+
+	want := []Entry{
+		{Severity: logging.Error, Message: "db write failed", Details: map[string]string{"error": "timeout"}},
+		{Severity: logging.Error, Message: "db write failed", Details: map[string]string{"throttle": "still happening", "count": "4"}},
+		{Severity: logging.Error, Message: "cache miss storm", Details: map[string]string{}},
+		{Severity: logging.Error, Message: "db write failed", Details: map[string]string{"error": "timeout", "throttle": "stopped", "count": "5"}},
+		{Severity: logging.Info, Message: "recovered", Details: map[string]string{}},
+	}
+	for i := range entries {
+		entries[i].Labels, entries[i].Timestamp = nil, time.Time{}
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v\nwant %+v", entries, want)
+	}
+}
@@ -0,0 +1,38 @@
+package cloudlogging
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"cloud.google.com/go/logging"
+)
+
+// WithUTF8Sanitize sets whether invalid UTF-8 in messages and detail values is
+// replaced before sending, since Cloud Logging rejects such entries. It is
+// enabled by default; the first sanitized entry is reported with a Warning.
+func WithUTF8Sanitize(enabled bool) Option {
+	return func(l *Logger) {
+		l.sanitizeUTF8 = enabled
+	}
+}
+
+func (l *Logger) sanitize(backupData interface{}) {
+	data, ok := backupData.(map[string]string)
+	if !l.sanitizeUTF8 || !ok {
+		return
+	}
+
+	sanitized := false
+	for k, v := range data {
+		if !utf8.ValidString(v) {
+			data[k] = strings.ToValidUTF8(v, string(utf8.RuneError))
+			sanitized = true
+		}
+	}
+	if sanitized {
+		l.utf8Warning.Do(func() {
+			warning := payload("invalid UTF-8 replaced in log entry")
+			l.deliver(logging.Entry{Payload: warning, Severity: logging.Warning}, warning)
+		})
+	}
+}
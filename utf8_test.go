@@ -0,0 +1,32 @@
+package cloudlogging
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestSanitizeUTF8(t *testing.T) {
+	l := newTestLogger(io.Discard)
+	entries := l.Capture(func() {
+		l.Info("bad \xff byte", "raw", "\xfe\xfe")
+		l.Info("again \xff")
+	})
+
+	want := []Entry{
+		{Severity: logging.Warning, Message: "invalid UTF-8 replaced in log entry", Details: map[string]string{}, Labels: map[string]string{}},
+		{Severity: logging.Info, Message: "bad � byte", Details: map[string]string{"raw": "�"}, Labels: map[string]string{}},
+		{Severity: logging.Info, Message: "again �", Details: map[string]string{}, Labels: map[string]string{}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v\nwant %+v", entries, want)
+	}
+
+	l = newTestLogger(io.Discard, WithUTF8Sanitize(false))
+	entries = l.Capture(func() { l.Info("bad \xff byte") })
+	if len(entries) != 1 || entries[0].Message != "bad \xff byte" {
+		t.Errorf("entries with sanitizing disabled = %+v, want the message unchanged", entries)
+	}
+}
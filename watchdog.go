@@ -0,0 +1,121 @@
+package cloudlogging
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+// maxReconnectBackoff bounds the wait between reconnects, in watchdog windows.
+const maxReconnectBackoff = 16
+
+type watchdog struct {
+	stalled int32 // accessed atomically
+
+	mu       sync.Mutex
+	flushing bool
+	started  time.Time
+	gen      int // the connection generation being flushed
+	retryAt  time.Time
+	backoff  time.Duration
+}
+
+// WithDeliveryWatchdog flushes the client every window and, when a flush does
+// not complete within window, considers delivery stalled: entries go to the
+// backup logger, as in fallback mode, until a flush succeeds again. This
+// catches a client that neither delivers nor reports errors.
+//
+// On a stall the client is replaced by a new one, for this logger and those
+// created from it by Named. Should the new client stall too, or fail to be
+// created, the next attempt waits a window, then twice as long after each
+// attempt up to 16 windows. Entries the replaced client holds are sent if it
+// recovers before Close. Flush durations are measured with the WithClock
+// clock.
+func WithDeliveryWatchdog(window time.Duration) Option {
+	return func(l *Logger) {
+		l.watchdogWindow = window
+	}
+}
+
+func (l *Logger) startWatchdog() {
+	if l.watchdogWindow <= 0 || l.conn == nil {
+		return
+	}
+	l.every(l.watchdogWindow, l.checkDelivery)
+}
+
+// checkDelivery starts a flush of the client. While the previous one is still
+// running after the watchdog window, it marks delivery stalled and replaces
+// the client, when the backoff allows, to flush the new one instead.
+func (l *Logger) checkDelivery() {
+	w := &l.watchdog
+	now := l.now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.flushing {
+		if now.Sub(w.started) < l.watchdogWindow {
+			return
+		}
+		atomic.StoreInt32(&w.stalled, 1)
+		if now.Before(w.retryAt) || !l.reconnect(now) {
+			return
+		}
+	}
+	_, gen := l.conn.current()
+	w.flushing = true
+	w.started = now
+	w.gen = gen
+	l.goBackground(func(<-chan struct{}) {
+		l.watchedFlush(gen)
+	})
+}
+
+// reconnect replaces the client, reporting whether it did, and sets when the
+// next attempt may be made. It is called with the watchdog locked.
+func (l *Logger) reconnect(now time.Time) bool {
+	w := &l.watchdog
+	if w.backoff == 0 {
+		w.backoff = l.watchdogWindow
+	}
+	w.retryAt = now.Add(w.backoff)
+	if w.backoff < maxReconnectBackoff*l.watchdogWindow {
+		w.backoff *= 2
+	}
+
+	if err := l.conn.reconnect(l.systemCtx); err != nil {
+		l.recordError(err, logging.Default)
+		l.backup.Printf("cloudlogging: reconnecting after stalled delivery: %v", err)
+		return false
+	}
+	return true
+}
+
+// watchedFlush flushes the client and records whether it did so within the
+// watchdog window. The result of a flush of a replaced client is ignored.
+func (l *Logger) watchedFlush(gen int) {
+	err := l.flushAll()
+
+	w := &l.watchdog
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if gen != w.gen {
+		return
+	}
+	w.flushing = false
+	if err != nil {
+		return
+	}
+	if l.now().Sub(w.started) < l.watchdogWindow {
+		atomic.StoreInt32(&w.stalled, 0)
+		w.retryAt = time.Time{}
+		w.backoff = 0
+	} else {
+		atomic.StoreInt32(&w.stalled, 1)
+	}
+}
+
+func (l *Logger) deliveryStalled() bool {
+	return atomic.LoadInt32(&l.watchdog.stalled) == 1
+}
@@ -0,0 +1,131 @@
+package cloudlogging
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock for WithClock that only moves when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestCheckDeliveryMarksLongFlushStalled(t *testing.T) {
+	clock := newFakeClock()
+	srv := &fakeServer{}
+	l := newServedLogger(t, srv, new(bytes.Buffer), WithClock(clock.Now), WithDeliveryWatchdog(time.Minute))
+	defer l.Close()
+
+	// A flush started now and still running; the backoff forbids reconnecting.
+	l.watchdog.flushing = true
+	l.watchdog.started = clock.Now()
+	l.watchdog.retryAt = clock.Now().Add(time.Hour)
+
+	clock.Advance(30 * time.Second)
+	l.checkDelivery()
+	if l.deliveryStalled() {
+		t.Fatal("stalled before the window elapsed")
+	}
+
+	clock.Advance(30 * time.Second)
+	l.checkDelivery()
+	if !l.deliveryStalled() {
+		t.Fatal("not stalled once the window elapsed")
+	}
+	if _, gen := l.conn.current(); gen != 0 {
+		t.Errorf("reconnected %d times during the backoff", gen)
+	}
+}
+
+func TestWatchdogReconnectsStalledClient(t *testing.T) {
+	clock := newFakeClock()
+	srv := &fakeServer{block: make(chan struct{})}
+	var backup bytes.Buffer
+	l := newServedLogger(t, srv, &backup, WithClock(clock.Now), WithDeliveryWatchdog(time.Minute))
+	old, _ := l.conn.current()
+
+	l.Info("before stall")
+	l.checkDelivery() // the flush blocks in the server
+	clock.Advance(time.Minute)
+	l.checkDelivery()
+
+	client, gen := l.conn.current()
+	if gen != 1 || client == old {
+		t.Fatalf("client not replaced after a stall (generation %d)", gen)
+	}
+	// The new client has nothing to flush, so delivery resumes.
+	deadline := time.Now().Add(5 * time.Second)
+	for l.deliveryStalled() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if l.deliveryStalled() {
+		t.Fatal("still stalled after the new client flushed")
+	}
+
+	close(srv.block)
+	l.Info("after reconnect")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var delivered bool
+	for _, entry := range srv.received() {
+		if entry.GetJsonPayload().GetFields()["msg"].GetStringValue() == "after reconnect" {
+			delivered = true
+		}
+	}
+	if !delivered {
+		t.Errorf("entry logged after reconnecting was not delivered; backup: %s", backup.String())
+	}
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	clock := newFakeClock()
+	l := newServedLogger(t, &fakeServer{}, new(bytes.Buffer), WithClock(clock.Now), WithDeliveryWatchdog(time.Minute))
+	defer l.Close()
+
+	start := clock.Now()
+	for i, want := range []time.Duration{1, 2, 4, 8, 16, 16} {
+		l.watchdog.mu.Lock()
+		l.reconnect(start)
+		retryAt := l.watchdog.retryAt
+		l.watchdog.mu.Unlock()
+		if got := retryAt.Sub(start); got != want*time.Minute {
+			t.Errorf("attempt %d: next reconnect after %v, want %v", i, got, want*time.Minute)
+		}
+	}
+}
+
+func TestStalledDeliveryUsesBackupNotPostCancelWriter(t *testing.T) {
+	var backup, postCancel bytes.Buffer
+	l := newTestLogger(&backup, WithPostCancelWriter(&postCancel))
+	l.watchdog.stalled = 1
+
+	l.Info("while stalled")
+
+	if !strings.Contains(backup.String(), "while stalled") {
+		t.Errorf("backup = %q, want the entry", backup.String())
+	}
+	if postCancel.Len() != 0 {
+		t.Errorf("post-cancel writer = %q, want nothing before cancellation", postCancel.String())
+	}
+}
@@ -0,0 +1,36 @@
+package cloudlogging
+
+import (
+	"io"
+	"log"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/logging"
+)
+
+type severityWriter struct {
+	mu       sync.Mutex
+	logger   *Logger
+	severity logging.Severity
+}
+
+// WriterFor returns an io.Writer that logs every write as one entry at the
+// given severity. A trailing newline is stripped from the message.
+func (l *Logger) WriterFor(severity logging.Severity) io.Writer {
+	return &severityWriter{logger: l, severity: severity}
+}
+
+func (w *severityWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.logger.log(w.severity, strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// StdLogger returns a *log.Logger whose output is logged at the given
+// severity, for dependencies that only accept the standard library logger.
+func (l *Logger) StdLogger(severity logging.Severity) *log.Logger {
+	return log.New(l.WriterFor(severity), "", 0)
+}